@@ -8,27 +8,163 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/oapi-codegen/runtime"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for AuditEntryAction.
+const (
+	AuditEntryActionApproved AuditEntryAction = "approved"
+	AuditEntryActionRejected AuditEntryAction = "rejected"
+)
+
 // Defines values for BookingStatus.
 const (
+	BookingStatusCancelled BookingStatus = "cancelled"
 	BookingStatusConfirmed BookingStatus = "confirmed"
 	BookingStatusPending   BookingStatus = "pending"
 	BookingStatusRejected  BookingStatus = "rejected"
 )
 
+// Defines values for BookingValueTier.
+const (
+	High   BookingValueTier = "high"
+	Low    BookingValueTier = "low"
+	Medium BookingValueTier = "medium"
+)
+
+// Defines values for BookingDecisionStatus.
+const (
+	BookingDecisionStatusConfirmed BookingDecisionStatus = "confirmed"
+	BookingDecisionStatusRejected  BookingDecisionStatus = "rejected"
+)
+
+// Defines values for BookingFlagEvaluationMatchedBy.
+const (
+	Default      BookingFlagEvaluationMatchedBy = "default"
+	FlagDisabled BookingFlagEvaluationMatchedBy = "flag_disabled"
+	Rollout      BookingFlagEvaluationMatchedBy = "rollout"
+	Segment      BookingFlagEvaluationMatchedBy = "segment"
+)
+
+// Defines values for BookingListV2Version.
+const (
+	N2 BookingListV2Version = "2"
+)
+
+// Defines values for DecisionOutcome.
+const (
+	ALREADYTERMINAL    DecisionOutcome = "ALREADY_TERMINAL"
+	APPROVED           DecisionOutcome = "APPROVED"
+	AUTOAPPROVALACTIVE DecisionOutcome = "AUTO_APPROVAL_ACTIVE"
+	REJECTED           DecisionOutcome = "REJECTED"
+	UPSTREAMERROR      DecisionOutcome = "UPSTREAM_ERROR"
+)
+
+// Defines values for JobStatus.
+const (
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusPending   JobStatus = "pending"
+	JobStatusSucceeded JobStatus = "succeeded"
+)
+
 // Defines values for GetApiBookingsParamsStatus.
 const (
+	GetApiBookingsParamsStatusCancelled GetApiBookingsParamsStatus = "cancelled"
 	GetApiBookingsParamsStatusConfirmed GetApiBookingsParamsStatus = "confirmed"
 	GetApiBookingsParamsStatusPending   GetApiBookingsParamsStatus = "pending"
 	GetApiBookingsParamsStatusRejected  GetApiBookingsParamsStatus = "rejected"
 )
 
+// Defines values for GetApiBookingsParamsSort.
+const (
+	GetApiBookingsParamsSortBookingId GetApiBookingsParamsSort = "booking_id"
+	GetApiBookingsParamsSortCheckin   GetApiBookingsParamsSort = "checkin"
+	GetApiBookingsParamsSortPrice     GetApiBookingsParamsSort = "price"
+	GetApiBookingsParamsSortStatus    GetApiBookingsParamsSort = "status"
+)
+
+// Defines values for GetApiBookingsParamsOrder.
+const (
+	GetApiBookingsParamsOrderAsc  GetApiBookingsParamsOrder = "asc"
+	GetApiBookingsParamsOrderDesc GetApiBookingsParamsOrder = "desc"
+)
+
+// Defines values for GetApiBookingsExportParamsStatus.
+const (
+	GetApiBookingsExportParamsStatusCancelled GetApiBookingsExportParamsStatus = "cancelled"
+	GetApiBookingsExportParamsStatusConfirmed GetApiBookingsExportParamsStatus = "confirmed"
+	GetApiBookingsExportParamsStatusPending   GetApiBookingsExportParamsStatus = "pending"
+	GetApiBookingsExportParamsStatusRejected  GetApiBookingsExportParamsStatus = "rejected"
+)
+
+// Defines values for GetApiV2BookingsParamsStatus.
+const (
+	Cancelled GetApiV2BookingsParamsStatus = "cancelled"
+	Confirmed GetApiV2BookingsParamsStatus = "confirmed"
+	Pending   GetApiV2BookingsParamsStatus = "pending"
+	Rejected  GetApiV2BookingsParamsStatus = "rejected"
+)
+
+// Defines values for GetApiV2BookingsParamsSort.
+const (
+	GetApiV2BookingsParamsSortBookingId GetApiV2BookingsParamsSort = "booking_id"
+	GetApiV2BookingsParamsSortCheckin   GetApiV2BookingsParamsSort = "checkin"
+	GetApiV2BookingsParamsSortPrice     GetApiV2BookingsParamsSort = "price"
+	GetApiV2BookingsParamsSortStatus    GetApiV2BookingsParamsSort = "status"
+)
+
+// Defines values for GetApiV2BookingsParamsOrder.
+const (
+	GetApiV2BookingsParamsOrderAsc  GetApiV2BookingsParamsOrder = "asc"
+	GetApiV2BookingsParamsOrderDesc GetApiV2BookingsParamsOrder = "desc"
+)
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action             *AuditEntryAction `json:"action,omitempty"`
+	AutoApproval       *bool             `json:"auto_approval,omitempty"`
+	AvailableRooms     *int              `json:"available_rooms,omitempty"`
+	BookingId          *string           `json:"booking_id,omitempty"`
+	ConfirmationNumber *string           `json:"confirmation_number,omitempty"`
+	HotelId            *string           `json:"hotel_id,omitempty"`
+
+	// Note Operator note attached to a manual approval
+	Note *string `json:"note,omitempty"`
+
+	// Reason Rejection reason, localized for auto-rejections
+	Reason *string `json:"reason,omitempty"`
+
+	// ReasonCode Reason key for rejections, e.g. no_availability
+	ReasonCode *string    `json:"reason_code,omitempty"`
+	Tier       *string    `json:"tier,omitempty"`
+	Timestamp  *time.Time `json:"timestamp,omitempty"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// AuditHistory defines model for AuditHistory.
+type AuditHistory struct {
+	BookingId *string       `json:"booking_id,omitempty"`
+	Entries   *[]AuditEntry `json:"entries,omitempty"`
+
+	// Total Number of entries recorded for the booking
+	Total *int `json:"total,omitempty"`
+}
+
+// BatchApproveRequest defines model for BatchApproveRequest.
+type BatchApproveRequest struct {
+	// BookingIds IDs of the bookings to approve
+	BookingIds []string `json:"booking_ids"`
+}
+
 // Booking defines model for Booking.
 type Booking struct {
+	// AvailableRooms Hotel's current availability for the booking's dates, when include_availability is set and the lookup succeeded
+	AvailableRooms     *int                 `json:"available_rooms,omitempty"`
 	BookingId          *string              `json:"booking_id,omitempty"`
 	Checkin            *openapi_types.Date  `json:"checkin,omitempty"`
 	Checkout           *openapi_types.Date  `json:"checkout,omitempty"`
@@ -39,54 +175,449 @@ type Booking struct {
 	HotelId            *string              `json:"hotel_id,omitempty"`
 	Status             *BookingStatus       `json:"status,omitempty"`
 	TotalPrice         *float32             `json:"total_price,omitempty"`
+
+	// ValueTier Local price bucket from VALUE_TIER_MEDIUM_FROM and VALUE_TIER_HIGH_FROM, also passed to flag evaluations as value_tier
+	ValueTier *BookingValueTier `json:"value_tier,omitempty"`
 }
 
 // BookingStatus defines model for Booking.Status.
 type BookingStatus string
 
+// BookingValueTier Local price bucket from VALUE_TIER_MEDIUM_FROM and VALUE_TIER_HIGH_FROM, also passed to flag evaluations as value_tier
+type BookingValueTier string
+
+// BookingCreatedEvent defines model for BookingCreatedEvent.
+type BookingCreatedEvent struct {
+	BookingId string  `json:"booking_id"`
+	HotelId   *string `json:"hotel_id,omitempty"`
+}
+
+// BookingDecision defines model for BookingDecision.
+type BookingDecision struct {
+	// AvailableRooms Rooms available at decision time, when include_availability is set
+	AvailableRooms *int    `json:"available_rooms,omitempty"`
+	BookingId      *string `json:"booking_id,omitempty"`
+
+	// ConfirmationExpiresAt When the hotel releases the confirmation, set by the approval tier's SLA
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+	Message               *string    `json:"message,omitempty"`
+
+	// Note Operator note attached to a manual approval
+	Note *string `json:"note,omitempty"`
+
+	// Outcome Machine-readable outcome of an approve or reject request. APPROVED and REJECTED accompany successful decisions; ALREADY_TERMINAL, AUTO_APPROVAL_ACTIVE and UPSTREAM_ERROR accompany errors.
+	Outcome *DecisionOutcome `json:"outcome,omitempty"`
+
+	// Reason Reason for rejection
+	Reason *string                `json:"reason,omitempty"`
+	Status *BookingDecisionStatus `json:"status,omitempty"`
+}
+
+// BookingDecisionStatus defines model for BookingDecision.Status.
+type BookingDecisionStatus string
+
+// BookingFlagEvaluation defines model for BookingFlagEvaluation.
+type BookingFlagEvaluation struct {
+	// Enabled Result of a boolean flag
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// EntityId Entity the flag was evaluated for
+	EntityId string `json:"entity_id"`
+
+	// Error Set when the flag could not be evaluated
+	Error   *string `json:"error,omitempty"`
+	FlagKey string  `json:"flag_key"`
+
+	// Match Whether a rule or rollout matched, rather than the flag's default deciding
+	Match bool `json:"match"`
+
+	// MatchedBy What decided the result: a segment rule of a variant flag, a rollout of a boolean flag, the flag's default or the flag being disabled. Rule IDs aren't exposed by the Flipt SDK.
+	MatchedBy *BookingFlagEvaluationMatchedBy `json:"matched_by,omitempty"`
+	Reason    string                          `json:"reason"`
+
+	// SegmentKeys Segments matched by a variant flag
+	SegmentKeys []string `json:"segment_keys"`
+
+	// Variant Result of a variant flag
+	Variant *string `json:"variant,omitempty"`
+}
+
+// BookingFlagEvaluationMatchedBy What decided the result: a segment rule of a variant flag, a rollout of a boolean flag, the flag's default or the flag being disabled. Rule IDs aren't exposed by the Flipt SDK.
+type BookingFlagEvaluationMatchedBy string
+
+// BookingFlags defines model for BookingFlags.
+type BookingFlags struct {
+	BookingId string                  `json:"booking_id"`
+	Flags     []BookingFlagEvaluation `json:"flags"`
+}
+
+// BookingList defines model for BookingList.
+type BookingList struct {
+	Bookings *[]Booking `json:"bookings,omitempty"`
+	Status   *string    `json:"status,omitempty"`
+	Total    *int       `json:"total,omitempty"`
+}
+
+// BookingListV2 Versioned envelope around a booking list
+type BookingListV2 struct {
+	Data BookingList `json:"data"`
+
+	// Version Response envelope version
+	Version BookingListV2Version `json:"version"`
+}
+
+// BookingListV2Version Response envelope version
+type BookingListV2Version string
+
+// BulkItemResult defines model for BulkItemResult.
+type BulkItemResult struct {
+	BookingId *string `json:"booking_id,omitempty"`
+	Error     *string `json:"error,omitempty"`
+	Status    *string `json:"status,omitempty"`
+}
+
+// BulkResult defines model for BulkResult.
+type BulkResult struct {
+	Failed    *int              `json:"failed,omitempty"`
+	Results   *[]BulkItemResult `json:"results,omitempty"`
+	Succeeded *int              `json:"succeeded,omitempty"`
+}
+
+// CancelConfirmedRequest defines model for CancelConfirmedRequest.
+type CancelConfirmedRequest struct {
+	// Confirm Must be true to perform the cancellation
+	Confirm bool `json:"confirm"`
+
+	// Reason Reason for cancellation, passed on to guests
+	Reason string `json:"reason"`
+}
+
+// DecisionOutcome Machine-readable outcome of an approve or reject request. APPROVED and REJECTED accompany successful decisions; ALREADY_TERMINAL, AUTO_APPROVAL_ACTIVE and UPSTREAM_ERROR accompany errors.
+type DecisionOutcome string
+
 // Error defines model for Error.
 type Error struct {
 	Error *string `json:"error,omitempty"`
+
+	// Outcome Machine-readable outcome of an approve or reject request. APPROVED and REJECTED accompany successful decisions; ALREADY_TERMINAL, AUTO_APPROVAL_ACTIVE and UPSTREAM_ERROR accompany errors.
+	Outcome *DecisionOutcome `json:"outcome,omitempty"`
+}
+
+// FlagDiagnostic defines model for FlagDiagnostic.
+type FlagDiagnostic struct {
+	FlagKey  *string `json:"flag_key,omitempty"`
+	FlagType *string `json:"flag_type,omitempty"`
+	Reason   *string `json:"reason,omitempty"`
+	Value    *string `json:"value,omitempty"`
+}
+
+// FlagStatus defines model for FlagStatus.
+type FlagStatus struct {
+	// AdminFeature Admin feature flag evaluated for the authenticated caller
+	AdminFeature *struct {
+		Enabled *bool `json:"enabled,omitempty"`
+
+		// EntityId The caller's user ID, or anonymous when unauthenticated
+		EntityId *string `json:"entity_id,omitempty"`
+		FlagKey  *string `json:"flag_key,omitempty"`
+	} `json:"admin_feature,omitempty"`
+
+	// ApprovalTier The approval-tier flag, evaluated for the preview booking when one is given
+	ApprovalTier *struct {
+		// Evaluated Whether a preview booking was given and the flag evaluated for it
+		Evaluated *bool   `json:"evaluated,omitempty"`
+		FlagKey   *string `json:"flag_key,omitempty"`
+
+		// Variant The tier the preview booking would get; omitted when not evaluated
+		Variant *string `json:"variant,omitempty"`
+	} `json:"approval_tier,omitempty"`
+	AutoApproval *struct {
+		Enabled *bool `json:"enabled,omitempty"`
+	} `json:"auto_approval,omitempty"`
+
+	// Evaluations Flipt evaluations made while serving this request, as reported by the SDK hook
+	Evaluations *[]FlagDiagnostic `json:"evaluations,omitempty"`
+}
+
+// HealthStatus defines model for HealthStatus.
+type HealthStatus struct {
+	Service *string `json:"service,omitempty"`
+	Status  *string `json:"status,omitempty"`
+}
+
+// HotelAvailability defines model for HotelAvailability.
+type HotelAvailability struct {
+	AvailableRooms *int    `json:"available_rooms,omitempty"`
+	Checkin        *string `json:"checkin,omitempty"`
+	Checkout       *string `json:"checkout,omitempty"`
+	Guests         *int    `json:"guests,omitempty"`
+	HotelId        *string `json:"hotel_id,omitempty"`
+}
+
+// Job defines model for Job.
+type Job struct {
+	BookingId *string    `json:"booking_id,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Error     *string    `json:"error,omitempty"`
+	JobId     *string    `json:"job_id,omitempty"`
+
+	// Outcome Outcome of a failed job; successful jobs carry it in result
+	Outcome   *DecisionOutcome `json:"outcome,omitempty"`
+	Result    *BookingDecision `json:"result,omitempty"`
+	Status    *JobStatus       `json:"status,omitempty"`
+	StatusUrl *string          `json:"status_url,omitempty"`
+	UpdatedAt *time.Time       `json:"updated_at,omitempty"`
+}
+
+// JobStatus defines model for Job.Status.
+type JobStatus string
+
+// Problem RFC 7807 problem details, returned when PROBLEM_JSON_ERRORS is enabled or the client sends Accept: application/problem+json
+type Problem struct {
+	Detail   *string `json:"detail,omitempty"`
+	Instance *string `json:"instance,omitempty"`
+
+	// Outcome Machine-readable outcome of an approve or reject request. APPROVED and REJECTED accompany successful decisions; ALREADY_TERMINAL, AUTO_APPROVAL_ACTIVE and UPSTREAM_ERROR accompany errors.
+	Outcome *DecisionOutcome `json:"outcome,omitempty"`
+	Status  *int             `json:"status,omitempty"`
+	Title   *string          `json:"title,omitempty"`
+	Type    *string          `json:"type,omitempty"`
+}
+
+// ReadinessStatus defines model for ReadinessStatus.
+type ReadinessStatus struct {
+	Error  *string `json:"error,omitempty"`
+	Status *string `json:"status,omitempty"`
+}
+
+// RejectStaleRequest defines model for RejectStaleRequest.
+type RejectStaleRequest struct {
+	// Confirm Must be true to perform the rejection
+	Confirm bool `json:"confirm"`
+
+	// Cutoff Reject pending bookings created before this time
+	Cutoff time.Time `json:"cutoff"`
+
+	// Reason Reason for rejection
+	Reason string `json:"reason"`
+}
+
+// TierStats defines model for TierStats.
+type TierStats struct {
+	// Since Start of the counted window
+	Since time.Time `json:"since"`
+
+	// Tiers Approvals per tier
+	Tiers map[string]int `json:"tiers"`
+
+	// Total Approvals across all tiers
+	Total int `json:"total"`
+
+	// Window Length of the counted window
+	Window string `json:"window"`
+}
+
+// WebhookResult defines model for WebhookResult.
+type WebhookResult struct {
+	BookingId *string `json:"booking_id,omitempty"`
+	Message   *string `json:"message,omitempty"`
+	Processed *bool   `json:"processed,omitempty"`
 }
 
 // GetApiBookingsParams defines parameters for GetApiBookings.
 type GetApiBookingsParams struct {
 	// Status Filter by booking status
 	Status *GetApiBookingsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// IncludeAvailability Enrich each booking with its hotel's current availability for the booking's dates. Lookups that fail or time out leave the booking without available_rooms.
+	IncludeAvailability *bool `form:"include_availability,omitempty" json:"include_availability,omitempty"`
+
+	// Sort Field to sort bookings by. Ties, and the default order, fall back to booking ID so the list is deterministic.
+	Sort *GetApiBookingsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Sort direction
+	Order *GetApiBookingsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
 }
 
 // GetApiBookingsParamsStatus defines parameters for GetApiBookings.
 type GetApiBookingsParamsStatus string
 
+// GetApiBookingsParamsSort defines parameters for GetApiBookings.
+type GetApiBookingsParamsSort string
+
+// GetApiBookingsParamsOrder defines parameters for GetApiBookings.
+type GetApiBookingsParamsOrder string
+
+// GetApiBookingsExportParams defines parameters for GetApiBookingsExport.
+type GetApiBookingsExportParams struct {
+	// Status Export only bookings with this status (default: all)
+	Status *GetApiBookingsExportParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetApiBookingsExportParamsStatus defines parameters for GetApiBookingsExport.
+type GetApiBookingsExportParamsStatus string
+
+// PostApiBookingsBookingIdApproveJSONBody defines parameters for PostApiBookingsBookingIdApprove.
+type PostApiBookingsBookingIdApproveJSONBody struct {
+	// Note Operator note recorded in the audit log and echoed in the response
+	Note *string `json:"note,omitempty"`
+}
+
+// PostApiBookingsBookingIdApproveParams defines parameters for PostApiBookingsBookingIdApprove.
+type PostApiBookingsBookingIdApproveParams struct {
+	// IncludeAvailability Include the hotel's current available room count in the response
+	IncludeAvailability *bool `form:"include_availability,omitempty" json:"include_availability,omitempty"`
+
+	// OverrideMaxAge Approve even if the booking has been pending longer than MAX_MANUAL_APPROVE_AGE
+	OverrideMaxAge *bool `form:"override_max_age,omitempty" json:"override_max_age,omitempty"`
+}
+
+// GetApiBookingsBookingIdAuditParams defines parameters for GetApiBookingsBookingIdAudit.
+type GetApiBookingsBookingIdAuditParams struct {
+	// Limit Maximum number of entries to return; larger values are capped at 200
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of most recent entries to skip
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
 // PostApiBookingsBookingIdRejectJSONBody defines parameters for PostApiBookingsBookingIdReject.
 type PostApiBookingsBookingIdRejectJSONBody struct {
 	// Reason Reason for rejection
 	Reason string `json:"reason"`
+
+	// ReasonCode Optional machine-readable reason code. Must be one of the codes attached to the reject-reason-codes flag's variant, or REJECT_REASON_CODES when the flag can't be evaluated
+	ReasonCode *string `json:"reason_code,omitempty"`
+}
+
+// GetApiFlagsParams defines parameters for GetApiFlags.
+type GetApiFlagsParams struct {
+	// HotelId Hotel of the booking to preview the approval tier for
+	HotelId *string `form:"hotel_id,omitempty" json:"hotel_id,omitempty"`
+
+	// TotalPrice Total price of the booking to preview the approval tier for
+	TotalPrice *float32 `form:"total_price,omitempty" json:"total_price,omitempty"`
+}
+
+// GetApiHotelsHotelIdAvailabilityParams defines parameters for GetApiHotelsHotelIdAvailability.
+type GetApiHotelsHotelIdAvailabilityParams struct {
+	// Checkin Check-in date (YYYY-MM-DD)
+	Checkin string `form:"checkin" json:"checkin"`
+
+	// Checkout Check-out date (YYYY-MM-DD), after check-in
+	Checkout string `form:"checkout" json:"checkout"`
+
+	// Guests Number of guests
+	Guests int `form:"guests" json:"guests"`
+}
+
+// GetApiV2BookingsParams defines parameters for GetApiV2Bookings.
+type GetApiV2BookingsParams struct {
+	// Status Filter by booking status
+	Status *GetApiV2BookingsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// IncludeAvailability Enrich each booking with its hotel's current availability for the booking's dates. Lookups that fail or time out leave the booking without available_rooms.
+	IncludeAvailability *bool `form:"include_availability,omitempty" json:"include_availability,omitempty"`
+
+	// Sort Field to sort bookings by. Ties, and the default order, fall back to booking ID so the list is deterministic.
+	Sort *GetApiV2BookingsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Sort direction
+	Order *GetApiV2BookingsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
 }
 
+// GetApiV2BookingsParamsStatus defines parameters for GetApiV2Bookings.
+type GetApiV2BookingsParamsStatus string
+
+// GetApiV2BookingsParamsSort defines parameters for GetApiV2Bookings.
+type GetApiV2BookingsParamsSort string
+
+// GetApiV2BookingsParamsOrder defines parameters for GetApiV2Bookings.
+type GetApiV2BookingsParamsOrder string
+
+// PostApiWebhooksBookingCreatedParams defines parameters for PostApiWebhooksBookingCreated.
+type PostApiWebhooksBookingCreatedParams struct {
+	// XWebhookSignature HMAC-SHA256 signature of the request body in the form sha256=<hex>
+	XWebhookSignature *string `json:"X-Webhook-Signature,omitempty"`
+}
+
+// PostApiBookingsBatchApproveJSONRequestBody defines body for PostApiBookingsBatchApprove for application/json ContentType.
+type PostApiBookingsBatchApproveJSONRequestBody = BatchApproveRequest
+
+// PostApiBookingsRejectStaleJSONRequestBody defines body for PostApiBookingsRejectStale for application/json ContentType.
+type PostApiBookingsRejectStaleJSONRequestBody = RejectStaleRequest
+
+// PostApiBookingsBookingIdApproveJSONRequestBody defines body for PostApiBookingsBookingIdApprove for application/json ContentType.
+type PostApiBookingsBookingIdApproveJSONRequestBody PostApiBookingsBookingIdApproveJSONBody
+
 // PostApiBookingsBookingIdRejectJSONRequestBody defines body for PostApiBookingsBookingIdReject for application/json ContentType.
 type PostApiBookingsBookingIdRejectJSONRequestBody PostApiBookingsBookingIdRejectJSONBody
 
+// PostApiHotelsHotelIdCancelConfirmedJSONRequestBody defines body for PostApiHotelsHotelIdCancelConfirmed for application/json ContentType.
+type PostApiHotelsHotelIdCancelConfirmedJSONRequestBody = CancelConfirmedRequest
+
+// PostApiWebhooksBookingCreatedJSONRequestBody defines body for PostApiWebhooksBookingCreated for application/json ContentType.
+type PostApiWebhooksBookingCreatedJSONRequestBody = BookingCreatedEvent
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Get bookings
 	// (GET /api/bookings)
 	GetApiBookings(w http.ResponseWriter, r *http.Request, params GetApiBookingsParams)
+	// Approve a batch of bookings
+	// (POST /api/bookings/batch-approve)
+	PostApiBookingsBatchApprove(w http.ResponseWriter, r *http.Request)
+	// Export bookings as CSV
+	// (GET /api/bookings/export)
+	GetApiBookingsExport(w http.ResponseWriter, r *http.Request, params GetApiBookingsExportParams)
+	// Reject stale pending bookings
+	// (POST /api/bookings/reject-stale)
+	PostApiBookingsRejectStale(w http.ResponseWriter, r *http.Request)
+	// Stream booking decisions
+	// (GET /api/bookings/stream)
+	GetApiBookingsStream(w http.ResponseWriter, r *http.Request)
 	// Get booking by ID
 	// (GET /api/bookings/{booking_id})
 	GetApiBookingsBookingId(w http.ResponseWriter, r *http.Request, bookingId string)
 	// Approve booking
 	// (POST /api/bookings/{booking_id}/approve)
-	PostApiBookingsBookingIdApprove(w http.ResponseWriter, r *http.Request, bookingId string)
+	PostApiBookingsBookingIdApprove(w http.ResponseWriter, r *http.Request, bookingId string, params PostApiBookingsBookingIdApproveParams)
+	// Get booking audit history
+	// (GET /api/bookings/{booking_id}/audit)
+	GetApiBookingsBookingIdAudit(w http.ResponseWriter, r *http.Request, bookingId string, params GetApiBookingsBookingIdAuditParams)
+	// Explain flag evaluations for a booking
+	// (GET /api/bookings/{booking_id}/flags)
+	GetApiBookingsBookingIdFlags(w http.ResponseWriter, r *http.Request, bookingId string)
 	// Reject booking
 	// (POST /api/bookings/{booking_id}/reject)
 	PostApiBookingsBookingIdReject(w http.ResponseWriter, r *http.Request, bookingId string)
 	// Get flag status
 	// (GET /api/flags)
-	GetApiFlags(w http.ResponseWriter, r *http.Request)
+	GetApiFlags(w http.ResponseWriter, r *http.Request, params GetApiFlagsParams)
+	// Check hotel availability
+	// (GET /api/hotels/{hotel_id}/availability)
+	GetApiHotelsHotelIdAvailability(w http.ResponseWriter, r *http.Request, hotelId string, params GetApiHotelsHotelIdAvailabilityParams)
+	// Cancel a hotel's confirmed bookings
+	// (POST /api/hotels/{hotel_id}/cancel-confirmed)
+	PostApiHotelsHotelIdCancelConfirmed(w http.ResponseWriter, r *http.Request, hotelId string)
+	// Get async approval job
+	// (GET /api/jobs/{job_id})
+	GetApiJobsJobId(w http.ResponseWriter, r *http.Request, jobId string)
+	// Get approvals per tier
+	// (GET /api/stats/tiers)
+	GetApiStatsTiers(w http.ResponseWriter, r *http.Request)
+	// Get bookings (v2)
+	// (GET /api/v2/bookings)
+	GetApiV2Bookings(w http.ResponseWriter, r *http.Request, params GetApiV2BookingsParams)
+	// Booking created webhook
+	// (POST /api/webhooks/booking-created)
+	PostApiWebhooksBookingCreated(w http.ResponseWriter, r *http.Request, params PostApiWebhooksBookingCreatedParams)
 	// Health check
 	// (GET /health)
 	GetHealth(w http.ResponseWriter, r *http.Request)
+	// Readiness check
+	// (GET /ready)
+	GetReady(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -113,6 +644,30 @@ func (siw *ServerInterfaceWrapper) GetApiBookings(w http.ResponseWriter, r *http
 		return
 	}
 
+	// ------------- Optional query parameter "include_availability" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_availability", r.URL.Query(), &params.IncludeAvailability)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_availability", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetApiBookings(w, r, params)
 	}))
@@ -124,6 +679,71 @@ func (siw *ServerInterfaceWrapper) GetApiBookings(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r)
 }
 
+// PostApiBookingsBatchApprove operation middleware
+func (siw *ServerInterfaceWrapper) PostApiBookingsBatchApprove(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostApiBookingsBatchApprove(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiBookingsExport operation middleware
+func (siw *ServerInterfaceWrapper) GetApiBookingsExport(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiBookingsExportParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiBookingsExport(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostApiBookingsRejectStale operation middleware
+func (siw *ServerInterfaceWrapper) PostApiBookingsRejectStale(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostApiBookingsRejectStale(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiBookingsStream operation middleware
+func (siw *ServerInterfaceWrapper) GetApiBookingsStream(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiBookingsStream(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // GetApiBookingsBookingId operation middleware
 func (siw *ServerInterfaceWrapper) GetApiBookingsBookingId(w http.ResponseWriter, r *http.Request) {
 	var err error
@@ -161,8 +781,94 @@ func (siw *ServerInterfaceWrapper) PostApiBookingsBookingIdApprove(w http.Respon
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostApiBookingsBookingIdApproveParams
+
+	// ------------- Optional query parameter "include_availability" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_availability", r.URL.Query(), &params.IncludeAvailability)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_availability", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "override_max_age" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "override_max_age", r.URL.Query(), &params.OverrideMaxAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "override_max_age", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostApiBookingsBookingIdApprove(w, r, bookingId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiBookingsBookingIdAudit operation middleware
+func (siw *ServerInterfaceWrapper) GetApiBookingsBookingIdAudit(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "booking_id" -------------
+	var bookingId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "booking_id", r.PathValue("booking_id"), &bookingId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "booking_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiBookingsBookingIdAuditParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostApiBookingsBookingIdApprove(w, r, bookingId)
+		siw.Handler.GetApiBookingsBookingIdAudit(w, r, bookingId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiBookingsBookingIdFlags operation middleware
+func (siw *ServerInterfaceWrapper) GetApiBookingsBookingIdFlags(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "booking_id" -------------
+	var bookingId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "booking_id", r.PathValue("booking_id"), &bookingId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "booking_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiBookingsBookingIdFlags(w, r, bookingId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -198,8 +904,251 @@ func (siw *ServerInterfaceWrapper) PostApiBookingsBookingIdReject(w http.Respons
 
 // GetApiFlags operation middleware
 func (siw *ServerInterfaceWrapper) GetApiFlags(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiFlagsParams
+
+	// ------------- Optional query parameter "hotel_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hotel_id", r.URL.Query(), &params.HotelId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hotel_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_price" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_price", r.URL.Query(), &params.TotalPrice)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_price", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiFlags(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiHotelsHotelIdAvailability operation middleware
+func (siw *ServerInterfaceWrapper) GetApiHotelsHotelIdAvailability(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "hotel_id" -------------
+	var hotelId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "hotel_id", r.PathValue("hotel_id"), &hotelId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hotel_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiHotelsHotelIdAvailabilityParams
+
+	// ------------- Required query parameter "checkin" -------------
+
+	if paramValue := r.URL.Query().Get("checkin"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "checkin"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "checkin", r.URL.Query(), &params.Checkin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "checkin", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "checkout" -------------
+
+	if paramValue := r.URL.Query().Get("checkout"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "checkout"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "checkout", r.URL.Query(), &params.Checkout)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "checkout", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "guests" -------------
+
+	if paramValue := r.URL.Query().Get("guests"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "guests"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "guests", r.URL.Query(), &params.Guests)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "guests", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiHotelsHotelIdAvailability(w, r, hotelId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostApiHotelsHotelIdCancelConfirmed operation middleware
+func (siw *ServerInterfaceWrapper) PostApiHotelsHotelIdCancelConfirmed(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "hotel_id" -------------
+	var hotelId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "hotel_id", r.PathValue("hotel_id"), &hotelId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hotel_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostApiHotelsHotelIdCancelConfirmed(w, r, hotelId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiJobsJobId operation middleware
+func (siw *ServerInterfaceWrapper) GetApiJobsJobId(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "job_id" -------------
+	var jobId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "job_id", r.PathValue("job_id"), &jobId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "job_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetApiFlags(w, r)
+		siw.Handler.GetApiJobsJobId(w, r, jobId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiStatsTiers operation middleware
+func (siw *ServerInterfaceWrapper) GetApiStatsTiers(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiStatsTiers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetApiV2Bookings operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV2Bookings(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV2BookingsParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_availability" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_availability", r.URL.Query(), &params.IncludeAvailability)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_availability", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApiV2Bookings(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostApiWebhooksBookingCreated operation middleware
+func (siw *ServerInterfaceWrapper) PostApiWebhooksBookingCreated(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostApiWebhooksBookingCreatedParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Webhook-Signature" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Webhook-Signature")]; found {
+		var XWebhookSignature string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Webhook-Signature", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Webhook-Signature", valueList[0], &XWebhookSignature, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Webhook-Signature", Err: err})
+			return
+		}
+
+		params.XWebhookSignature = &XWebhookSignature
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostApiWebhooksBookingCreated(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -222,6 +1171,19 @@ func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
+// GetReady operation middleware
+func (siw *ServerInterfaceWrapper) GetReady(w http.ResponseWriter, r *http.Request) {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReady(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -343,11 +1305,24 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 	}
 
 	m.HandleFunc("GET "+options.BaseURL+"/api/bookings", wrapper.GetApiBookings)
+	m.HandleFunc("POST "+options.BaseURL+"/api/bookings/batch-approve", wrapper.PostApiBookingsBatchApprove)
+	m.HandleFunc("GET "+options.BaseURL+"/api/bookings/export", wrapper.GetApiBookingsExport)
+	m.HandleFunc("POST "+options.BaseURL+"/api/bookings/reject-stale", wrapper.PostApiBookingsRejectStale)
+	m.HandleFunc("GET "+options.BaseURL+"/api/bookings/stream", wrapper.GetApiBookingsStream)
 	m.HandleFunc("GET "+options.BaseURL+"/api/bookings/{booking_id}", wrapper.GetApiBookingsBookingId)
 	m.HandleFunc("POST "+options.BaseURL+"/api/bookings/{booking_id}/approve", wrapper.PostApiBookingsBookingIdApprove)
+	m.HandleFunc("GET "+options.BaseURL+"/api/bookings/{booking_id}/audit", wrapper.GetApiBookingsBookingIdAudit)
+	m.HandleFunc("GET "+options.BaseURL+"/api/bookings/{booking_id}/flags", wrapper.GetApiBookingsBookingIdFlags)
 	m.HandleFunc("POST "+options.BaseURL+"/api/bookings/{booking_id}/reject", wrapper.PostApiBookingsBookingIdReject)
 	m.HandleFunc("GET "+options.BaseURL+"/api/flags", wrapper.GetApiFlags)
+	m.HandleFunc("GET "+options.BaseURL+"/api/hotels/{hotel_id}/availability", wrapper.GetApiHotelsHotelIdAvailability)
+	m.HandleFunc("POST "+options.BaseURL+"/api/hotels/{hotel_id}/cancel-confirmed", wrapper.PostApiHotelsHotelIdCancelConfirmed)
+	m.HandleFunc("GET "+options.BaseURL+"/api/jobs/{job_id}", wrapper.GetApiJobsJobId)
+	m.HandleFunc("GET "+options.BaseURL+"/api/stats/tiers", wrapper.GetApiStatsTiers)
+	m.HandleFunc("GET "+options.BaseURL+"/api/v2/bookings", wrapper.GetApiV2Bookings)
+	m.HandleFunc("POST "+options.BaseURL+"/api/webhooks/booking-created", wrapper.PostApiWebhooksBookingCreated)
 	m.HandleFunc("GET "+options.BaseURL+"/health", wrapper.GetHealth)
+	m.HandleFunc("GET "+options.BaseURL+"/ready", wrapper.GetReady)
 
 	return m
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AuditEntry records a single decision taken on a booking
+type AuditEntry struct {
+	Timestamp          time.Time `json:"timestamp"`
+	BookingID          string    `json:"booking_id"`
+	HotelID            string    `json:"hotel_id"`
+	Action             string    `json:"action"`
+	AutoApproval       bool      `json:"auto_approval"`
+	Tier               string    `json:"tier,omitempty"`
+	ReasonCode         string    `json:"reason_code,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	ConfirmationNumber string    `json:"confirmation_number,omitempty"`
+	AvailableRooms     *int      `json:"available_rooms,omitempty"`
+	Note               string    `json:"note,omitempty"`
+}
+
+// AuditLog keeps the most recent audit entries in memory so they can be read
+// back per booking. Every entry is also written to the structured log, which
+// is the durable audit sink.
+type AuditLog struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+	next    int
+	full    bool
+}
+
+func NewAuditLog(size int) *AuditLog {
+	return &AuditLog{entries: make([]AuditEntry, max(size, 1))}
+}
+
+// Record appends an entry, evicting the oldest once the log is full.
+func (a *AuditLog) Record(entry AuditEntry) {
+	slog.Info("audit",
+		"booking_id", entry.BookingID,
+		"hotel_id", entry.HotelID,
+		"action", entry.Action,
+		"auto_approval", entry.AutoApproval,
+		"tier", entry.Tier,
+		"reason", entry.Reason,
+		"note", entry.Note,
+	)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries[a.next] = entry
+	a.next = (a.next + 1) % len(a.entries)
+	if a.next == 0 {
+		a.full = true
+	}
+}
+
+// ForBooking returns the recorded entries for a booking, oldest first.
+func (a *AuditLog) ForBooking(bookingID string) []AuditEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	start, n := 0, a.next
+	if a.full {
+		start, n = a.next, len(a.entries)
+	}
+
+	var entries []AuditEntry
+	for i := range n {
+		entry := a.entries[(start+i)%len(a.entries)]
+		if entry.BookingID == bookingID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthMiddlewareWebhookBypass(t *testing.T) {
+	keys := map[string]Principal{"approver-key": {User: "ops", Role: RoleApprover}}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	for _, tc := range []struct {
+		name, secret, key string
+		want              int
+	}{
+		{"signed webhooks skip keys", "secret", "", http.StatusOK},
+		{"unsigned webhooks need a key", "", "", http.StatusUnauthorized},
+		{"unsigned webhooks accept a key", "", "approver-key", http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks/booking-created", nil)
+			if tc.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.key)
+			}
+			rec := httptest.NewRecorder()
+			authMiddleware(keys, tc.secret)(ok).ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareRoles(t *testing.T) {
+	keys := map[string]Principal{
+		"viewer-key":   {User: "support", Role: RoleViewer},
+		"approver-key": {User: "ops", Role: RoleApprover},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	for _, tc := range []struct {
+		method, path, key string
+		want              int
+	}{
+		{http.MethodGet, "/health", "", http.StatusOK},
+		{http.MethodGet, "/api/bookings", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/bookings", "unknown-key", http.StatusUnauthorized},
+		{http.MethodGet, "/api/bookings", "viewer-key", http.StatusOK},
+		{http.MethodPost, "/api/bookings/b1/approve", "viewer-key", http.StatusForbidden},
+		{http.MethodPost, "/api/bookings/b1/approve", "approver-key", http.StatusOK},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.key != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.key)
+		}
+		rec := httptest.NewRecorder()
+		authMiddleware(keys, "")(ok).ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s %s with %q = %d, want %d", tc.method, tc.path, tc.key, rec.Code, tc.want)
+		}
+	}
+}
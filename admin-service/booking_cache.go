@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type cachedBooking struct {
+	booking hotelclient.Booking
+	expires time.Time
+}
+
+// BookingCache caches bookings fetched by ID for a short TTL so a request
+// that reads the same booking more than once, or several requests in quick
+// succession, share one hotel-service call. Entries are invalidated when the
+// service updates the booking.
+type BookingCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedBooking
+
+	// generation counts invalidations. A fetch that started before an
+	// invalidation may have read the old status, so it isn't cached.
+	generation uint64
+}
+
+func NewBookingCache(ttl time.Duration) *BookingCache {
+	return &BookingCache{ttl: ttl, entries: map[string]cachedBooking{}}
+}
+
+func (c *BookingCache) enabled() bool {
+	return c.ttl > 0
+}
+
+// Get returns a copy of the cached booking, along with the generation to
+// pass to Put when the booking isn't cached and has to be fetched.
+func (c *BookingCache) Get(bookingID string) (*hotelclient.Booking, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[bookingID]
+	if !ok {
+		return nil, c.generation, false
+	}
+	if !timeNow().Before(entry.expires) {
+		delete(c.entries, bookingID)
+		return nil, c.generation, false
+	}
+	booking := entry.booking
+	return &booking, c.generation, true
+}
+
+// Put caches a booking fetched after the Get that returned generation,
+// unless a booking was invalidated in the meantime.
+func (c *BookingCache) Put(booking *hotelclient.Booking, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := timeNow()
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[booking.BookingID] = cachedBooking{booking: *booking, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops a booking, called whenever its status may have changed.
+func (c *BookingCache) Invalidate(bookingID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, bookingID)
+	c.generation++
+}
+
+// getBooking fetches a booking by ID through the booking cache. Callers get
+// their own copy, which they may modify.
+func (s *AdminService) getBooking(ctx context.Context, bookingID string) (*hotelclient.Booking, error) {
+	if !s.bookings.enabled() {
+		return s.hotelClient.GetBooking(ctx, bookingID)
+	}
+
+	booking, generation, ok := s.bookings.Get(bookingID)
+	if ok {
+		metricsFromContext(ctx).Add(ctx, s.bookingCacheCounter, 1, attribute.String("result", "hit"))
+		return booking, nil
+	}
+	metricsFromContext(ctx).Add(ctx, s.bookingCacheCounter, 1, attribute.String("result", "miss"))
+
+	booking, err := s.hotelClient.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	s.bookings.Put(booking, generation)
+
+	fetched := *booking
+	return &fetched, nil
+}
+
+// getBookingForUpdate fetches a booking the caller is about to approve or
+// reject straight from the hotel service, bypassing the booking cache, so a
+// status changed elsewhere within the cache TTL isn't decided again.
+func (s *AdminService) getBookingForUpdate(ctx context.Context, bookingID string) (*hotelclient.Booking, error) {
+	return s.hotelClient.GetBooking(ctx, bookingID)
+}
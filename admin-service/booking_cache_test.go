@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newCachingService(t *testing.T, hotel *fakeHotelService) *AdminService {
+	t.Helper()
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	evaluator.setVariant("approval-tier", "standard")
+	return newTestService(t, evaluator, hotel, func(cfg *Config) {
+		cfg.BookingCacheTTL = time.Minute
+	})
+}
+
+func TestDecisionsBypassBookingCache(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		target string
+		body   string
+	}{
+		{name: "approve", target: "/api/bookings/b1/approve"},
+		{name: "reject", target: "/api/bookings/b1/reject", body: `{"reason": "no"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+			svc := newCachingService(t, hotel)
+
+			// Cache the pending booking, then decide it elsewhere
+			if rec := serve(svc, http.MethodGet, "/api/bookings/b1", ""); rec.Code != http.StatusOK {
+				t.Fatalf("GET status = %d", rec.Code)
+			}
+			hotel.setStatus("b1", "cancelled")
+
+			if rec := serve(svc, http.MethodPost, tt.target, tt.body); rec.Code == http.StatusOK {
+				t.Errorf("%s of a booking cancelled elsewhere succeeded: %s", tt.name, rec.Body)
+			}
+			if patches := hotel.requested(http.MethodPatch); len(patches) != 0 {
+				t.Errorf("booking cancelled elsewhere was updated: %v", patches)
+			}
+		})
+	}
+}
+
+func TestBatchApproveBypassesBookingCache(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	svc := newCachingService(t, hotel)
+
+	serve(svc, http.MethodGet, "/api/bookings/b1", "")
+	hotel.setStatus("b1", "cancelled")
+
+	serve(svc, http.MethodPost, "/api/bookings/batch-approve", `{"booking_ids": ["b1"]}`)
+	if patches := hotel.requested(http.MethodPatch); len(patches) != 0 {
+		t.Errorf("booking cancelled elsewhere was updated: %v", patches)
+	}
+}
+
+func TestDecisionInvalidatesBookingCache(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	svc := newCachingService(t, hotel)
+
+	serve(svc, http.MethodGet, "/api/bookings/b1", "")
+	serve(svc, http.MethodGet, "/api/bookings/b1", "")
+	if gets := hotel.requested(http.MethodGet); len(gets) != 1 {
+		t.Fatalf("cached GETs reached the hotel service %d times, want 1", len(gets))
+	}
+
+	if rec := serve(svc, http.MethodPost, "/api/bookings/b1/approve", ""); rec.Code != http.StatusOK {
+		t.Fatalf("approve status = %d: %s", rec.Code, rec.Body)
+	}
+	rec := serve(svc, http.MethodGet, "/api/bookings/b1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Cache-Control"); got == "no-store" {
+		t.Errorf("booking still served as pending after approval")
+	}
+}
+
+func TestBookingCacheSkipsFetchesOverlappingInvalidation(t *testing.T) {
+	cache := NewBookingCache(time.Minute)
+	booking := pendingBooking("b1", "hotel_1")
+
+	_, generation, ok := cache.Get("b1")
+	if ok {
+		t.Fatal("empty cache returned a booking")
+	}
+	cache.Invalidate("b1")
+	cache.Put(&booking, generation)
+	if _, _, ok := cache.Get("b1"); ok {
+		t.Error("booking fetched before an invalidation was cached")
+	}
+
+	_, generation, _ = cache.Get("b1")
+	cache.Put(&booking, generation)
+	if _, _, ok := cache.Get("b1"); !ok {
+		t.Error("booking fetched after the invalidation wasn't cached")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	sdk "go.flipt.io/flipt-client"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Evaluation reasons reported by the Flipt SDK
+const (
+	evaluationReasonMatch        = "MATCH_EVALUATION_REASON"
+	evaluationReasonDefault      = "DEFAULT_EVALUATION_REASON"
+	evaluationReasonFlagDisabled = "FLAG_DISABLED_EVALUATION_REASON"
+)
+
+// matchedBy names what decided a flag's result from the SDK's evaluation
+// reason. The SDK doesn't expose rule IDs: a matched variant flag is
+// identified by its segments, and a matched boolean flag only by having hit
+// a rollout.
+func matchedBy(reason string, variant bool) string {
+	switch reason {
+	case evaluationReasonMatch:
+		if variant {
+			return "segment"
+		}
+		return "rollout"
+	case evaluationReasonDefault:
+		return "default"
+	case evaluationReasonFlagDisabled:
+		return "flag_disabled"
+	default:
+		return ""
+	}
+}
+
+// GetApiBookingsBookingIdFlags evaluates the flags the worker consults for a
+// booking with the same entity and context, and reports the matched segments
+// and evaluation reasons. It is read-only: nothing is decided, recorded or
+// shadow-evaluated.
+func (s *AdminService) GetApiBookingsBookingIdFlags(w http.ResponseWriter, r *http.Request, bookingID string) {
+	ctx, span := startHandlerSpan(r, "get_booking_flags")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("booking_id", bookingID))
+
+	booking, err := s.getBooking(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, hotelclient.ErrNotFound) {
+			span.SetAttributes(attribute.Bool("found", false))
+			respondError(w, r, http.StatusNotFound, "Booking not found")
+			return
+		}
+		log.Printf("Error fetching booking from hotel-service: %v", err)
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch booking")
+		return
+	}
+
+	requests := []*sdk.EvaluationRequest{
+		{
+			FlagKey:  "auto-approval",
+			EntityID: "worker",
+			Context:  s.evaluationContext(ctx, nil),
+		},
+		s.manualReviewRequest(ctx, booking),
+	}
+
+	flags := make([]BookingFlagEvaluation, 0, len(requests)+1)
+	for _, req := range requests {
+		eval := BookingFlagEvaluation{FlagKey: req.FlagKey, EntityID: req.EntityID, SegmentKeys: []string{}}
+		result, err := s.evaluator.EvaluateBoolean(ctx, req)
+		if err != nil {
+			eval.Error = err.Error()
+		} else {
+			eval.Enabled = &result.Enabled
+			eval.Match = result.Reason == evaluationReasonMatch
+			eval.MatchedBy = matchedBy(result.Reason, false)
+			eval.Reason = result.Reason
+		}
+		flags = append(flags, eval)
+	}
+
+	tierReq := s.approvalTierRequest(ctx, booking)
+	tier := BookingFlagEvaluation{FlagKey: tierReq.FlagKey, EntityID: tierReq.EntityID, SegmentKeys: []string{}}
+	result, err := s.evaluator.EvaluateVariant(ctx, tierReq)
+	if err != nil {
+		tier.Error = err.Error()
+	} else {
+		tier.Variant = result.VariantKey
+		tier.Match = result.Match
+		tier.MatchedBy = matchedBy(result.Reason, true)
+		tier.Reason = result.Reason
+		if result.SegmentKeys != nil {
+			tier.SegmentKeys = result.SegmentKeys
+		}
+	}
+	flags = append(flags, tier)
+
+	respondJSON(w, http.StatusOK, BookingFlagsResponse{BookingID: bookingID, Flags: flags})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestBookingFlagsReportWhatMatched(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	evaluator.setReason("auto-approval", evaluationReasonMatch)
+	evaluator.setBoolean("require-manual-review", false)
+	evaluator.setReason("require-manual-review", evaluationReasonDefault)
+	evaluator.setVariant("approval-tier", "vip")
+	evaluator.setReason("approval-tier", evaluationReasonMatch, "high-value-bookings")
+	svc := newTestService(t, evaluator, hotel, nil)
+
+	rec := serve(svc, http.MethodGet, "/api/bookings/b1/flags", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("flags = %d: %s", rec.Code, rec.Body)
+	}
+	var resp BookingFlagsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	byFlag := map[string]BookingFlagEvaluation{}
+	for _, flag := range resp.Flags {
+		byFlag[flag.FlagKey] = flag
+	}
+	// A rollout that matched to false is still a match
+	if f := byFlag["auto-approval"]; !f.Match || f.MatchedBy != "rollout" || f.Enabled == nil || *f.Enabled {
+		t.Errorf("auto-approval = %+v, want a matched rollout returning false", f)
+	}
+	if f := byFlag["require-manual-review"]; f.Match || f.MatchedBy != "default" {
+		t.Errorf("require-manual-review = %+v, want the default", f)
+	}
+	if f := byFlag["approval-tier"]; !f.Match || f.MatchedBy != "segment" || f.Variant != "vip" || !slices.Equal(f.SegmentKeys, []string{"high-value-bookings"}) {
+		t.Errorf("approval-tier = %+v, want vip from high-value-bookings", f)
+	}
+}
+
+func TestBookingFlagsReportEvaluationErrors(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", true)
+	evaluator.setBoolean("require-manual-review", false)
+	evaluator.setError("approval-tier", errors.New("flipt unavailable"))
+	svc := newTestService(t, evaluator, hotel, nil)
+
+	rec := serve(svc, http.MethodGet, "/api/bookings/b1/flags", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("flags = %d: %s", rec.Code, rec.Body)
+	}
+	var resp BookingFlagsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	tier := resp.Flags[len(resp.Flags)-1]
+	if tier.FlagKey != "approval-tier" || tier.Error != "flipt unavailable" || tier.MatchedBy != "" || tier.SegmentKeys == nil {
+		t.Errorf("approval-tier = %+v, want the evaluation error", tier)
+	}
+	if n := len(hotel.requested(http.MethodPatch)); n > 0 {
+		t.Errorf("read-only endpoint made %d updates", n)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// ApprovalEvent describes a completed approval passed to AfterApprove hooks
+type ApprovalEvent struct {
+	Booking               *hotelclient.Booking
+	Tier                  string
+	ConfirmationNumber    string
+	ConfirmationExpiresAt time.Time
+	AutoApproval          bool
+	Note                  string
+}
+
+// BookingHook lets custom logic such as fraud scoring, notifications or
+// auditing run around the approval flow without changing approveBooking.
+// Hooks are invoked in registration order.
+type BookingHook interface {
+	// BeforeApprove runs before a booking is confirmed. Returning an error
+	// blocks the approval and leaves the booking pending.
+	BeforeApprove(ctx context.Context, booking *hotelclient.Booking) error
+	// AfterApprove runs once a booking has been confirmed.
+	AfterApprove(ctx context.Context, event ApprovalEvent)
+}
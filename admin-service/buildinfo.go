@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Set at build time with -ldflags "-X main.buildVersion=... -X main.buildCommit=...".
+// Builds from a git checkout fall back to the VCS details Go embeds.
+var (
+	buildVersion string
+	buildCommit  string
+)
+
+// buildAttributes returns the span attributes identifying the running build:
+// version, git commit, Go version and hostname. They are constant for the
+// life of the process, so they add no cardinality to traces.
+func buildAttributes() []attribute.KeyValue {
+	version, commit := buildVersion, buildCommit
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && commit == "" {
+				commit = setting.Value
+			}
+		}
+	}
+	if version == "" {
+		version = "dev"
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return []attribute.KeyValue{
+		attribute.String("service.version", version),
+		attribute.String("vcs.commit", commit),
+		attribute.String("go.version", runtime.Version()),
+		attribute.String("host.name", hostname),
+	}
+}
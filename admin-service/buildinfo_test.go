@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRootSpansCarryBuildAttributes(t *testing.T) {
+	previousVersion, previousCommit := buildVersion, buildCommit
+	buildVersion, buildCommit = "v1.2.3", "abc123"
+	t.Cleanup(func() { buildVersion, buildCommit = previousVersion, previousCommit })
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := recordSpans(t)
+	handler := tracingMiddleware(nil, buildAttributes())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracer.Start(r.Context(), "child")
+		span.End()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/bookings", nil))
+
+	for _, span := range recorder.Ended() {
+		attrs := attribute.NewSet(span.Attributes()...)
+		if span.Name() == "child" {
+			if _, ok := attrs.Value("service.version"); ok {
+				t.Error("child span carries build attributes, want them on the root span only")
+			}
+			continue
+		}
+		for key, want := range map[attribute.Key]string{
+			"service.version": "v1.2.3",
+			"vcs.commit":      "abc123",
+			"go.version":      runtime.Version(),
+			"host.name":       hostname,
+		} {
+			if got, _ := attrs.Value(key); got.AsString() != want {
+				t.Errorf("root span %s = %q, want %q", key, got.AsString(), want)
+			}
+		}
+	}
+	if n := len(recorder.Ended()); n != 2 {
+		t.Errorf("recorded %d spans, want the root and its child", n)
+	}
+}
+
+func TestBuildAttributesFallBackWithoutLdflags(t *testing.T) {
+	previousVersion, previousCommit := buildVersion, buildCommit
+	buildVersion, buildCommit = "", ""
+	t.Cleanup(func() { buildVersion, buildCommit = previousVersion, previousCommit })
+
+	attrs := attribute.NewSet(buildAttributes()...)
+	for _, key := range []attribute.Key{"service.version", "vcs.commit"} {
+		if got, ok := attrs.Value(key); !ok || got.AsString() == "" {
+			t.Errorf("%s = %q without ldflags, want a fallback", key, got.AsString())
+		}
+	}
+}
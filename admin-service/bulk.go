@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+func (s *AdminService) PostApiBookingsRejectStale(w http.ResponseWriter, r *http.Request) {
+	ctx, span := startHandlerSpan(r, "reject_stale_bookings")
+	defer span.End()
+
+	var req api.RejectStaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Cutoff.IsZero() || req.Reason == "" {
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if !req.Confirm {
+		respondError(w, r, http.StatusBadRequest, "Rejecting stale bookings requires confirm to be true")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("cutoff", req.Cutoff.String()),
+		attribute.String("reason", req.Reason),
+	)
+
+	if s.autoApprovalEnabled(ctx) {
+		span.RecordError(errAutoApprovalEnabled)
+		respondError(w, r, http.StatusConflict, errAutoApprovalEnabled.Error())
+		return
+	}
+
+	bookings, err := s.getBookings(ctx, "pending")
+	if err != nil {
+		log.Printf("Error fetching bookings from hotel-service: %v", err)
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch bookings")
+		return
+	}
+
+	type staleBooking struct {
+		booking hotelclient.Booking
+		created time.Time
+	}
+	var stale []staleBooking
+	// Bookings of unknown age can't be checked against the cutoff; they are
+	// reported as failed so operators can look at them
+	var unknownAge []BulkItemResult
+	for _, booking := range bookings {
+		created, err := booking.Created()
+		if err != nil {
+			unknownAge = append(unknownAge, BulkItemResult{
+				BookingID: booking.BookingID,
+				Status:    "failed",
+				Error:     fmt.Sprintf("creation timestamp %q can't be parsed, so its age is unknown", booking.CreatedAt),
+			})
+			continue
+		}
+		if created.Before(req.Cutoff) {
+			stale = append(stale, staleBooking{booking: booking, created: created})
+		}
+	}
+	// Oldest first, so a run cut short has rejected the longest-waiting
+	// bookings and repeated runs over the same bookings behave the same
+	slices.SortStableFunc(stale, func(a, b staleBooking) int {
+		if c := a.created.Compare(b.created); c != 0 {
+			return c
+		}
+		return strings.Compare(a.booking.BookingID, b.booking.BookingID)
+	})
+	span.SetAttributes(
+		attribute.Int("stale_bookings", len(stale)),
+		attribute.Int("unknown_age_bookings", len(unknownAge)),
+	)
+
+	results := make([]BulkItemResult, len(stale))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.cfg.BatchRejectConcurrency, 1))
+	for i, item := range stale {
+		g.Go(func() error {
+			result := BulkItemResult{BookingID: item.booking.BookingID, Status: "rejected"}
+			if err := s.batchRejectOne(gctx, &item.booking, req.Reason); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	g.Wait()
+	results = append(results, unknownAge...)
+
+	summary := summarizeBulk(results)
+	metricsFromContext(ctx).Add(ctx, s.bulkRejectCounter, int64(summary.Succeeded),
+		attribute.String("operation", "reject_stale"),
+	)
+	metricsFromContext(ctx).Add(ctx, s.bulkRejectRunCounter, 1,
+		attribute.String("operation", "reject_stale"),
+		attribute.String("outcome", bulkOutcome(ctx, summary)),
+	)
+	log.Printf("Rejected %d stale bookings created before %s (%d failed)", summary.Succeeded, req.Cutoff, summary.Failed)
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// PostApiBookingsBatchApprove approves each listed booking, bounding the
+// number of concurrent hotel-service updates so a large batch can't overwhelm
+// the upstream.
+func (s *AdminService) PostApiBookingsBatchApprove(w http.ResponseWriter, r *http.Request) {
+	ctx, span := startHandlerSpan(r, "batch_approve_bookings")
+	defer span.End()
+
+	var req api.BatchApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.BookingIds) == 0 {
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	span.SetAttributes(attribute.Int("batch_size", len(req.BookingIds)))
+
+	if s.autoApprovalEnabled(ctx) {
+		span.RecordError(errAutoApprovalEnabled)
+		respondError(w, r, http.StatusConflict, errAutoApprovalEnabled.Error())
+		return
+	}
+
+	results := make([]BulkItemResult, len(req.BookingIds))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.cfg.BatchApproveConcurrency, 1))
+	for i, bookingID := range req.BookingIds {
+		g.Go(func() error {
+			result := BulkItemResult{BookingID: bookingID, Status: "confirmed"}
+			if err := s.batchApproveOne(gctx, bookingID); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	g.Wait()
+
+	summary := summarizeBulk(results)
+	log.Printf("Batch approved %d bookings (%d failed)", summary.Succeeded, summary.Failed)
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+func (s *AdminService) batchApproveOne(ctx context.Context, bookingID string) error {
+	// Stop starting new approvals once the client has gone away
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	booking, err := s.getBookingForUpdate(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	_, err = s.approveBooking(ctx, booking, nil, "", false)
+	return err
+}
+
+func (s *AdminService) batchRejectOne(ctx context.Context, booking *hotelclient.Booking, reason string) error {
+	// Stop starting new rejections once the client has gone away
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.rejectBooking(ctx, booking, reasonStale, reason, false)
+}
+
+// bulkOutcome summarizes a bulk run for metrics: complete when every booking
+// succeeded, cancelled when the client went away before it finished, and
+// partial when some bookings failed otherwise
+func bulkOutcome(ctx context.Context, summary BulkSummary) string {
+	switch {
+	case summary.Failed == 0:
+		return "complete"
+	case ctx.Err() != nil:
+		return "cancelled"
+	default:
+		return "partial"
+	}
+}
+
+func summarizeBulk(results []BulkItemResult) BulkSummary {
+	summary := BulkSummary{Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+	return summary
+}
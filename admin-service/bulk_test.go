@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newBulkTestService(t *testing.T, autoApproval bool) (*AdminService, *fakeHotelService) {
+	t.Helper()
+	old := pendingBooking("old", "hotel_1")
+	old.CreatedAt = "2029-12-01T00:00:00"
+	older := pendingBooking("older", "hotel_1")
+	older.CreatedAt = "2029-11-01T00:00:00"
+	undated := pendingBooking("undated", "hotel_1")
+	undated.CreatedAt = "yesterday"
+	hotel := newFakeHotelService(t, pendingBooking("new", "hotel_1"), old, undated, older)
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", autoApproval)
+	return newTestService(t, evaluator, hotel, nil), hotel
+}
+
+func TestRejectStaleRejectsOldestFirstAndFailsUnknownAges(t *testing.T) {
+	svc, hotel := newBulkTestService(t, false)
+
+	rec := serve(svc, http.MethodPost, "/api/bookings/reject-stale",
+		`{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired", "confirm": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reject-stale = %d: %s", rec.Code, rec.Body)
+	}
+	var summary BulkSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []BulkItemResult{
+		{BookingID: "older", Status: "rejected"},
+		{BookingID: "old", Status: "rejected"},
+		{BookingID: "undated", Status: "failed"},
+	}
+	if summary.Succeeded != 2 || summary.Failed != 1 || len(summary.Results) != len(want) {
+		t.Fatalf("summary = %+v", summary)
+	}
+	for i, result := range summary.Results {
+		if result.BookingID != want[i].BookingID || result.Status != want[i].Status {
+			t.Errorf("result %d = %+v, want %s %s", i, result, want[i].BookingID, want[i].Status)
+		}
+	}
+	if summary.Results[2].Error == "" {
+		t.Error("booking of unknown age failed without an error")
+	}
+	for id, status := range map[string]string{"older": "rejected", "old": "rejected", "undated": "pending", "new": "pending"} {
+		if got := hotel.booking(id).Status; got != status {
+			t.Errorf("booking %s status = %s, want %s", id, got, status)
+		}
+	}
+}
+
+func TestRejectStaleRefusals(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		autoApproval bool
+		body         string
+		want         int
+	}{
+		{"without confirm", false, `{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired"}`, http.StatusBadRequest},
+		{"without reason", false, `{"cutoff": "2030-01-01T00:00:00Z", "confirm": true}`, http.StatusBadRequest},
+		{"while auto-approval is enabled", true, `{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired", "confirm": true}`, http.StatusConflict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, hotel := newBulkTestService(t, tc.autoApproval)
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/reject-stale", tc.body)
+			if rec.Code != tc.want {
+				t.Errorf("reject-stale = %d, want %d", rec.Code, tc.want)
+			}
+			if patched := hotel.requested(http.MethodPatch); len(patched) > 0 {
+				t.Errorf("refused request updated %v", patched)
+			}
+		})
+	}
+}
+
+// rejectStaleRuns returns the admin_bulk_reject_runs_total counts by outcome
+func rejectStaleRuns(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	runs := map[string]int64{}
+	for _, dp := range collectMetric(t, reader, "admin_bulk_reject_runs_total").Data.(metricdata.Sum[int64]).DataPoints {
+		outcome, _ := dp.Attributes.Value("outcome")
+		runs[outcome.AsString()] += dp.Value
+	}
+	return runs
+}
+
+func TestRejectStaleContinuesPastFailures(t *testing.T) {
+	reader := recordMetrics(t)
+	svc, hotel := newBulkTestService(t, false)
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/older") {
+			http.Error(w, `{"detail": "Unavailable"}`, http.StatusServiceUnavailable)
+			return true
+		}
+		return false
+	}
+
+	rec := serve(svc, http.MethodPost, "/api/bookings/reject-stale",
+		`{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired", "confirm": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reject-stale = %d: %s", rec.Code, rec.Body)
+	}
+	var summary BulkSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Succeeded != 1 || summary.Failed != 2 {
+		t.Errorf("summary = %+v, want 1 succeeded and 2 failed", summary)
+	}
+	if len(summary.Results) == 0 || summary.Results[0].BookingID != "older" || summary.Results[0].Error == "" {
+		t.Errorf("results = %+v, want older to fail with an error", summary.Results)
+	}
+	for id, status := range map[string]string{"older": "pending", "old": "rejected"} {
+		if got := hotel.booking(id).Status; got != status {
+			t.Errorf("booking %s status = %s, want %s", id, got, status)
+		}
+	}
+
+	rejections := collectMetric(t, reader, "admin_bulk_rejections_total").Data.(metricdata.Sum[int64])
+	if len(rejections.DataPoints) != 1 || rejections.DataPoints[0].Value != 1 {
+		t.Errorf("admin_bulk_rejections_total = %+v, want 1", rejections.DataPoints)
+	}
+	if runs := rejectStaleRuns(t, reader); runs["partial"] != 1 || len(runs) != 1 {
+		t.Errorf("runs by outcome = %v, want one partial run", runs)
+	}
+}
+
+func TestRejectStaleStopsWhenCancelled(t *testing.T) {
+	reader := recordMetrics(t)
+	var bookings []hotelclient.Booking
+	for i := range 5 {
+		booking := pendingBooking(fmt.Sprintf("b%d", i), "hotel_1")
+		booking.CreatedAt = fmt.Sprintf("2029-12-0%dT00:00:00", i+1)
+		bookings = append(bookings, booking)
+	}
+	hotel := newFakeHotelService(t, bookings...)
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.BatchRejectConcurrency = 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The client goes away while the first rejection is in flight
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPatch {
+			cancel()
+		}
+		return false
+	}
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/bookings/reject-stale",
+		strings.NewReader(`{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired", "confirm": true}`))
+	rec := httptest.NewRecorder()
+	api.HandlerFromMux(svc, http.NewServeMux()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reject-stale = %d: %s", rec.Code, rec.Body)
+	}
+	var summary BulkSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if patched := hotel.requested(http.MethodPatch); len(patched) != 1 {
+		t.Errorf("updates after cancellation = %v, want only the first", patched)
+	}
+	if len(summary.Results) != len(bookings) || summary.Failed < len(bookings)-1 {
+		t.Errorf("summary = %+v, want every booking after the first to fail", summary)
+	}
+	for _, result := range summary.Results[1:] {
+		if result.Error == "" {
+			t.Errorf("booking %s processed after cancellation", result.BookingID)
+		}
+	}
+	if runs := rejectStaleRuns(t, reader); runs["cancelled"] != 1 || len(runs) != 1 {
+		t.Errorf("runs by outcome = %v, want one cancelled run", runs)
+	}
+}
+
+func TestRejectStaleConcurrency(t *testing.T) {
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			var bookings []hotelclient.Booking
+			for i := range 6 {
+				booking := pendingBooking(fmt.Sprintf("b%d", i), "hotel_1")
+				// Listed newest first, so processing order has to come from sorting
+				booking.CreatedAt = fmt.Sprintf("2029-12-0%dT00:00:00", 6-i)
+				bookings = append(bookings, booking)
+			}
+			hotel := newFakeHotelService(t, bookings...)
+
+			var mu sync.Mutex
+			inFlight, peak := 0, 0
+			hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+				if r.Method == http.MethodPatch {
+					mu.Lock()
+					inFlight++
+					peak = max(peak, inFlight)
+					mu.Unlock()
+					time.Sleep(20 * time.Millisecond)
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				}
+				return false
+			}
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", false)
+			svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.BatchRejectConcurrency = concurrency })
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/reject-stale",
+				`{"cutoff": "2030-01-01T00:00:00Z", "reason": "Expired", "confirm": true}`)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("reject-stale = %d: %s", rec.Code, rec.Body)
+			}
+			if peak != concurrency {
+				t.Errorf("peak concurrent updates = %d, want %d", peak, concurrency)
+			}
+			if concurrency == 1 {
+				want := []string{"b5", "b4", "b3", "b2", "b1", "b0"}
+				patched := hotel.requested(http.MethodPatch)
+				for i, uri := range patched {
+					if i < len(want) && !strings.HasSuffix(uri, "/"+want[i]) {
+						t.Errorf("update %d = %s, want booking %s", i, uri, want[i])
+					}
+				}
+				if len(patched) != len(want) {
+					t.Errorf("updates = %v, want %d", patched, len(want))
+				}
+			}
+		})
+	}
+}
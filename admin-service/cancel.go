@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// reasonHotelUnavailable is the reason code recorded for bookings cancelled
+// because their hotel can no longer honor them
+const reasonHotelUnavailable = "hotel_unavailable"
+
+// PostApiHotelsHotelIdCancelConfirmed cancels every confirmed booking at a
+// hotel that can no longer honor them, e.g. after a closure. Like the other
+// bulk operations it is destructive, so confirm must be set. The hotel
+// service only records the new status; the reason is kept in the audit log
+// and sent to webhook subscribers.
+func (s *AdminService) PostApiHotelsHotelIdCancelConfirmed(w http.ResponseWriter, r *http.Request, hotelID string) {
+	ctx, span := startHandlerSpan(r, "cancel_confirmed_bookings")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("hotel_id", hotelID))
+
+	var req api.CancelConfirmedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Reason == "" {
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if !req.Confirm {
+		respondError(w, r, http.StatusBadRequest, "Cancelling confirmed bookings requires confirm to be true")
+		return
+	}
+	span.SetAttributes(attribute.String("reason", req.Reason))
+
+	confirmed, err := s.hotelClient.Scoped(hotelclient.HotelScope{HotelIDs: []string{hotelID}}).GetBookings(ctx, "confirmed")
+	if err != nil {
+		log.Printf("Error fetching bookings from hotel-service: %v", err)
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch bookings")
+		return
+	}
+	span.SetAttributes(attribute.Int("confirmed_bookings", len(confirmed)))
+
+	results := make([]BulkItemResult, len(confirmed))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.cfg.BulkCancelConcurrency, 1))
+	for i, booking := range confirmed {
+		g.Go(func() error {
+			result := BulkItemResult{BookingID: booking.BookingID, Status: "cancelled"}
+			if err := s.cancelBooking(gctx, &booking, req.Reason); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	g.Wait()
+
+	summary := summarizeBulk(results)
+	log.Printf("Cancelled %d confirmed bookings at hotel %s (%d failed): %s", summary.Succeeded, hotelID, summary.Failed, req.Reason)
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// cancelBooking cancels a confirmed booking and notifies decision webhook
+// subscribers, through which guests are told.
+func (s *AdminService) cancelBooking(ctx context.Context, booking *hotelclient.Booking, reason string) error {
+	// Stop starting new cancellations once the client has gone away
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := s.updateBooking(ctx, booking.BookingID, hotelclient.BookingUpdateRequest{
+		Status: "cancelled",
+	})
+	if err != nil {
+		s.recordCancellation(ctx, booking, "failure")
+		return fmt.Errorf("failed to cancel booking: %w", err)
+	}
+	s.recordCancellation(ctx, booking, "success")
+
+	s.auditLog.Record(AuditEntry{
+		Timestamp:  timeNow(),
+		BookingID:  booking.BookingID,
+		HotelID:    booking.HotelID,
+		Action:     "cancelled",
+		ReasonCode: reasonHotelUnavailable,
+		Reason:     reason,
+	})
+	bookingEvent := BookingEvent{
+		BookingID: booking.BookingID,
+		HotelID:   booking.HotelID,
+		Status:    "cancelled",
+		Reason:    reason,
+		Timestamp: timeNow(),
+	}
+	s.events.Publish(bookingEvent)
+	s.notifier.Notify(ctx, bookingEvent)
+
+	log.Printf("Booking %s cancelled: %s", booking.BookingID, reason)
+	return nil
+}
+
+func (s *AdminService) recordCancellation(ctx context.Context, booking *hotelclient.Booking, result string) {
+	metricsFromContext(ctx).Add(ctx, s.cancellationCounter, 1,
+		attribute.String("hotel_id", booking.HotelID),
+		attribute.String("result", result),
+	)
+}
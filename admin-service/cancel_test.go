@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+func TestCancelConfirmedBoundsConcurrency(t *testing.T) {
+	var bookings []hotelclient.Booking
+	for i := range 6 {
+		booking := pendingBooking(fmt.Sprintf("b%d", i), "hotel_1")
+		booking.Status = "confirmed"
+		bookings = append(bookings, booking)
+	}
+	hotel := newFakeHotelService(t, bookings...)
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPatch {
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}
+		return false
+	}
+	svc := newTestService(t, newFakeEvaluator(), hotel, func(cfg *Config) { cfg.BulkCancelConcurrency = 2 })
+
+	rec := serve(svc, http.MethodPost, "/api/hotels/hotel_1/cancel-confirmed", `{"reason": "Closed for repairs", "confirm": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel-confirmed = %d: %s", rec.Code, rec.Body)
+	}
+	if peak != 2 {
+		t.Errorf("peak concurrent updates = %d, want 2", peak)
+	}
+	for _, booking := range bookings {
+		if status := hotel.booking(booking.BookingID).Status; status != "cancelled" {
+			t.Errorf("booking %s status = %s, want cancelled", booking.BookingID, status)
+		}
+	}
+}
+
+func TestCancelConfirmedOnlyFetchesTheHotel(t *testing.T) {
+	confirmed := func(id, hotelID string) hotelclient.Booking {
+		booking := pendingBooking(id, hotelID)
+		booking.Status = "confirmed"
+		return booking
+	}
+	hotel := newFakeHotelService(t, confirmed("b1", "hotel_1"), confirmed("b2", "hotel_2"), pendingBooking("b3", "hotel_1"))
+	svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+
+	rec := serve(svc, http.MethodPost, "/api/hotels/hotel_1/cancel-confirmed", `{"reason": "Closed for repairs", "confirm": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cancel-confirmed = %d: %s", rec.Code, rec.Body)
+	}
+
+	listed := hotel.requested(http.MethodGet)
+	if len(listed) != 1 || !strings.Contains(listed[0], "hotel_ids=hotel_1") {
+		t.Errorf("bookings listed with %v, want one list filtered to hotel_1", listed)
+	}
+	for id, status := range map[string]string{"b1": "cancelled", "b2": "confirmed", "b3": "pending"} {
+		if got := hotel.booking(id).Status; got != status {
+			t.Errorf("booking %s status = %s, want %s", id, got, status)
+		}
+	}
+}
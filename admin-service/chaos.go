@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+
+	sdk "go.flipt.io/flipt-client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errChaos = errors.New("chaos: injected failure")
+
+// Chaos randomly fails a fraction of calls at the hotel client and Flipt
+// boundaries, so retries and fallbacks can be demonstrated. It is only
+// created when CHAOS_ENABLED is explicitly true.
+type Chaos struct {
+	rate    float64
+	counter metric.Int64Counter
+}
+
+func NewChaos(rate float64) *Chaos {
+	counter, _ := meter.Int64Counter(
+		"admin_chaos_injected_failures_total",
+		metric.WithDescription("Total number of failures injected by chaos mode, by target"),
+	)
+	return &Chaos{rate: rate, counter: counter}
+}
+
+// inject reports whether the call to target should fail, recording the
+// injected failure.
+func (c *Chaos) inject(ctx context.Context, target string) error {
+	if rand.Float64() >= c.rate {
+		return nil
+	}
+	c.counter.Add(ctx, 1, metric.WithAttributes(attribute.String("target", target)))
+	trace.SpanFromContext(ctx).AddEvent("chaos.injected_failure", trace.WithAttributes(
+		attribute.String("target", target),
+	))
+	return fmt.Errorf("%s: %w", target, errChaos)
+}
+
+// Transport wraps an HTTP transport so requests fail before reaching the
+// network, like a dropped connection.
+func (c *Chaos) Transport(next http.RoundTripper) http.RoundTripper {
+	return chaosTransport{next: next, chaos: c}
+}
+
+type chaosTransport struct {
+	next  http.RoundTripper
+	chaos *Chaos
+}
+
+func (t chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.chaos.inject(req.Context(), "hotel"); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// ChaosEvaluator fails a fraction of flag evaluations before they reach
+// the wrapped evaluator.
+type ChaosEvaluator struct {
+	next  Evaluator
+	chaos *Chaos
+}
+
+func NewChaosEvaluator(next Evaluator, chaos *Chaos) *ChaosEvaluator {
+	return &ChaosEvaluator{next: next, chaos: chaos}
+}
+
+func (e *ChaosEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	if err := e.chaos.inject(ctx, "flipt"); err != nil {
+		return nil, err
+	}
+	return e.next.EvaluateBoolean(ctx, req)
+}
+
+func (e *ChaosEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	if err := e.chaos.inject(ctx, "flipt"); err != nil {
+		return nil, err
+	}
+	return e.next.EvaluateVariant(ctx, req)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// timeNow is the clock used for every time-based decision (expiry, staleness,
+// backoff) so it can be replaced with a fixed clock.
+var timeNow = time.Now
+
+// minPlausibleTime is a lower bound for the system clock; anything earlier
+// means the container clock was never synchronized.
+var minPlausibleTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// checkClock logs a warning when the clock looks implausible, since expiry
+// and staleness decisions compare booking timestamps against it.
+func checkClock() {
+	if now := timeNow(); now.Before(minPlausibleTime) {
+		log.Printf("Warning: system clock reads %s, which looks unsynchronized; time-based booking decisions may be wrong", now.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckClockWarnsWhenImplausible(t *testing.T) {
+	for _, tc := range []struct {
+		now  time.Time
+		warn bool
+	}{
+		{time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{minPlausibleTime.Add(-time.Second), true},
+		{minPlausibleTime, false},
+		{time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), false},
+	} {
+		setClock(t, tc.now)
+		logs := captureLog(t)
+
+		checkClock()
+		if warned := strings.Contains(logs.String(), "Warning: system clock"); warned != tc.warn {
+			t.Errorf("clock at %s: warned = %t, want %t (%q)", tc.now, warned, tc.warn, logs)
+		}
+	}
+}
+
+func TestPendingAgeUsesClock(t *testing.T) {
+	booking := pendingBooking("booking_1", "hotel_1")
+	created, err := booking.Created()
+	if err != nil {
+		t.Fatal(err)
+	}
+	setClock(t, created.Add(90*time.Minute))
+
+	if age, ok := pendingAge(&booking); !ok || age != 90*time.Minute {
+		t.Errorf("pendingAge = %s, %t, want 1h30m0s", age, ok)
+	}
+
+	booking.CreatedAt = ""
+	if _, ok := pendingAge(&booking); ok {
+		t.Error("pendingAge of a booking without a creation time is ok, want not ok")
+	}
+}
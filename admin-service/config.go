@@ -0,0 +1,727 @@
+package main
+
+import (
+	"cmp"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// Config holds the admin service configuration loaded from the environment
+type Config struct {
+	FliptURL         string
+	FliptNamespace   string
+	FliptEnvironment string
+	Port             string
+	HotelServiceURL  string
+
+	// HotelServiceHealthPath is the hotel service path pinged for readiness
+	HotelServiceHealthPath string
+	LogFormat              string
+
+	// LogFile is appended to instead of stdout when set, and reopened on
+	// SIGHUP for log rotation
+	LogFile string
+
+	// RequestTimeout is the total time budget for handling a request,
+	// shared by all downstream calls the handler makes
+	RequestTimeout time.Duration
+
+	// Instrumentation scope used for the service's tracer and meter
+	TracerName             string
+	MeterName              string
+	InstrumentationVersion string
+
+	// OTLP exporter retry settings for transient collector outages
+	OTLPRetryEnabled         bool
+	OTLPRetryInitialInterval time.Duration
+	OTLPRetryMaxInterval     time.Duration
+	OTLPRetryMaxElapsedTime  time.Duration
+
+	// OTLPBearerToken is sent as an Authorization bearer token on OTLP
+	// exports, for hosted backends
+	OTLPBearerToken string
+
+	// OTELDebugStdout prints spans and metrics to stdout for local
+	// development: "true" alongside OTLP export, "only" instead of it
+	OTELDebugStdout string
+
+	// OTELPropagators lists the trace context propagation formats used for
+	// incoming and outgoing requests, as in the standard OTEL_PROPAGATORS
+	OTELPropagators []string
+
+	// MetricIncludeBookingID adds booking_id to metric attributes. It is
+	// unbounded-cardinality and should only be enabled for debugging.
+	MetricIncludeBookingID bool
+
+	// HotelAvailabilityTimeout bounds each availability lookup made while
+	// processing a booking, independently of the shared HTTP client timeout.
+	HotelAvailabilityTimeout time.Duration
+
+	// WebhookSecret, when set, requires inbound webhooks to carry a valid
+	// HMAC-SHA256 signature of the request body.
+	WebhookSecret string
+
+	// HotelDenylist contains hotel IDs whose bookings are never auto-approved
+	// and always require manual review.
+	HotelDenylist []string
+
+	// HotelAllowlist restricts auto-approval to these hotel IDs when
+	// non-empty; the deny-list still wins for hotels on both lists
+	HotelAllowlist []string
+
+	// WorkerRateLimitBackoff is how long the worker pauses after being rate
+	// limited when the hotel service doesn't send a Retry-After header.
+	WorkerRateLimitBackoff time.Duration
+
+	// WorkerMaxSweepDuration caps how long a single worker sweep may run.
+	// Zero disables the cap.
+	WorkerMaxSweepDuration time.Duration
+
+	// WorkerReadyTimeout bounds how long the worker waits for Flipt and the
+	// hotel service to become reachable before starting. Zero skips the wait.
+	WorkerReadyTimeout time.Duration
+
+	// AuditLogSize is the number of recent audit entries kept in memory
+	AuditLogSize int
+
+	// ProblemJSONErrors returns all errors as RFC 7807 problem+json
+	ProblemJSONErrors bool
+
+	// EvaluationContext holds ambient keys (region, cluster) merged into
+	// every flag evaluation; per-call keys take precedence
+	EvaluationContext map[string]string
+
+	// EvaluationContextHeaders maps request headers to the evaluation
+	// context keys their values are added as, for evaluations made while
+	// serving the request
+	EvaluationContextHeaders map[string]string
+
+	// FliptRecordFile, when set, appends every Flipt evaluation to this file
+	FliptRecordFile string
+
+	// FliptReplayFile, when set, answers evaluations from a recorded file
+	// instead of Flipt
+	FliptReplayFile string
+
+	// BatchApproveConcurrency bounds concurrent hotel-service updates made by
+	// the batch approve endpoint
+	BatchApproveConcurrency int
+
+	// BatchRejectConcurrency bounds concurrent hotel-service updates made by
+	// bulk rejections
+	BatchRejectConcurrency int
+
+	// BulkCancelConcurrency bounds concurrent hotel-service updates made when
+	// cancelling a hotel's confirmed bookings
+	BulkCancelConcurrency int
+
+	// TracingExcludePaths are served without creating a span, keeping probe
+	// traffic out of traces
+	TracingExcludePaths []string
+
+	// TracingBuildAttributes adds the build version, git commit, Go version
+	// and hostname to every request's root span
+	TracingBuildAttributes bool
+
+	// ApprovalTierSLAs maps approval tiers to how long the hotel holds the
+	// confirmation; tiers not listed use ApprovalDefaultSLA
+	ApprovalTierSLAs   map[string]time.Duration
+	ApprovalDefaultSLA time.Duration
+
+	// HotelReadTimeout and HotelWriteTimeout bound each hotel-service read
+	// and booking update respectively
+	HotelReadTimeout  time.Duration
+	HotelWriteTimeout time.Duration
+
+	// ShadowApprovalTierFlagKey, when set, names a candidate approval-tier
+	// flag evaluated alongside the real one and only recorded
+	ShadowApprovalTierFlagKey string
+
+	// ApprovalTierFallbackFlags are variant flags evaluated in order, with
+	// the approval-tier entity and context, when approval-tier matches no
+	// variant; the first variant found is the tier
+	ApprovalTierFallbackFlags []string
+
+	// AsyncApproval makes manual approvals return 202 and complete in the
+	// background, pollable via /api/jobs/{job_id}
+	AsyncApproval bool
+
+	// JobStoreSize bounds the async approval jobs kept in memory; finished
+	// jobs expire after JobTTL
+	JobStoreSize int
+	JobTTL       time.Duration
+
+	// ApprovalKnownTiers are the approval-tier variants the service acts on;
+	// any other variant is replaced with ApprovalDefaultTier
+	ApprovalKnownTiers  []string
+	ApprovalDefaultTier string
+
+	// ConfirmationPrefix starts confirmation numbers; ConfirmationTierPrefixes
+	// replaces it for the approval tiers listed
+	ConfirmationPrefix       string
+	ConfirmationTierPrefixes map[string]string
+
+	// WorkerPollInterval is how often the auto-approval worker sweeps the
+	// service's own namespace
+	WorkerPollInterval time.Duration
+
+	// WorkerTenants maps Flipt namespaces to their own worker poll interval.
+	// Each tenant gets a worker that evaluates its namespace's flags. When
+	// empty, a single worker runs for FliptNamespace.
+	WorkerTenants map[string]time.Duration
+
+	// WorkerTenantHotels assigns hotels to tenant namespaces, so each
+	// pending booking is decided by one tenant's worker. Hotels not
+	// assigned belong to FliptNamespace.
+	WorkerTenantHotels map[string]string
+
+	// HTTP transport connection pooling, shared by the Flipt and hotel
+	// service clients
+	HTTPMaxIdleConns        int
+	HTTPMaxIdleConnsPerHost int
+	HTTPIdleConnTimeout     time.Duration
+
+	// DecisionCacheTTL is how long the worker reuses the availability and
+	// tier gathered for a still-pending booking. Zero disables the cache.
+	DecisionCacheTTL time.Duration
+
+	// WorkerShutdownSummary logs the worker's lifetime totals when it stops
+	WorkerShutdownSummary bool
+
+	// FliptEvaluationTimeout bounds each flag evaluation on top of the
+	// request deadline. Zero relies on the request deadline alone.
+	FliptEvaluationTimeout time.Duration
+
+	// DefaultLanguage is used to localize rejection reasons when the caller
+	// doesn't ask for a language, as for worker decisions
+	DefaultLanguage string
+
+	// MessageCatalog maps languages to localized text per reason key
+	MessageCatalog map[string]map[string]string
+
+	// SlowRequestThreshold logs API requests that take longer than this at
+	// warning level; 0 disables the slow request log
+	SlowRequestThreshold time.Duration
+
+	// ApprovalTierOverrides forces the approval tier for specific guest
+	// emails without evaluating Flipt, for reproducible demos
+	ApprovalTierOverrides map[string]string
+
+	// WorkerProcessingOrder is the order the worker decides a sweep's
+	// bookings in: as-returned, oldest-first, highest-price-first or
+	// soonest-checkin-first
+	WorkerProcessingOrder string
+
+	// HotelMaxRetries is how many times failed hotel service reads are
+	// retried, starting HotelRetryBackoff apart; 0 disables retries
+	HotelMaxRetries   int
+	HotelRetryBackoff time.Duration
+
+	// HotelRetryBudgetPercent caps hotel service retries to this percentage
+	// of requests across the client
+	HotelRetryBudgetPercent int
+
+	// MinAvailabilityBuffer is how many rooms a hotel must keep beyond a
+	// booking's for it to be auto-approved; bookings within the buffer are
+	// left pending for manual review
+	MinAvailabilityBuffer int
+
+	// WorkerTickSpanSampleRatio is the fraction of skipped worker ticks that
+	// are traced; ticks that run are always traced
+	WorkerTickSpanSampleRatio float64
+
+	// HotelRoutes overrides hotel service path templates per operation
+	HotelRoutes map[string]string
+
+	// AdminFeatureFlagKey is the boolean flag evaluated per authenticated
+	// admin for user-targeted rollouts
+	AdminFeatureFlagKey string
+
+	// Availability enrichment of booking lists: lookups run at most
+	// EnrichmentConcurrency at a time, each bounded by EnrichmentLookupTimeout
+	// and all of them by EnrichmentTimeout
+	EnrichmentConcurrency   int
+	EnrichmentLookupTimeout time.Duration
+	EnrichmentTimeout       time.Duration
+
+	// ChaosEnabled gates chaos mode, which fails ChaosFailureRate of hotel
+	// service calls and flag evaluations for resilience demos. Both must be
+	// set for any failure to be injected.
+	ChaosEnabled     bool
+	ChaosFailureRate float64
+
+	// ShutdownTimeout bounds graceful shutdown of the HTTP server and the
+	// background components after it; ComponentStopTimeout bounds each
+	// component so one stuck component can't use up the whole budget
+	ShutdownTimeout      time.Duration
+	ComponentStopTimeout time.Duration
+
+	// ShutdownDrainPeriod is how long new requests are answered with 503
+	// before the HTTP server stops accepting connections
+	ShutdownDrainPeriod time.Duration
+
+	// ValueTierMediumFrom and ValueTierHighFrom are the total prices at
+	// which a booking's value tier becomes medium and high
+	ValueTierMediumFrom float64
+	ValueTierHighFrom   float64
+
+	// HotelMaxRedirects is how many redirects a hotel-service request may
+	// follow, 0 to fail on any redirect
+	HotelMaxRedirects int
+
+	// AutoApprovalMinLeadDays and AutoApprovalMaxLeadDays bound how many
+	// days before checkin the worker may auto-approve a booking. The window
+	// applies once either is set; a zero maximum leaves it open-ended.
+	AutoApprovalMinLeadDays int
+	AutoApprovalMaxLeadDays int
+
+	// VerifyWrites reads each booking back after updating it to confirm the
+	// hotel service persisted the new status
+	VerifyWrites bool
+
+	// BookingCacheTTL is how long a booking fetched by ID is reused, 0 to
+	// disable the booking cache
+	BookingCacheTTL time.Duration
+
+	// AccessLog logs one line per request at AccessLogLevel
+	AccessLog      bool
+	AccessLogLevel slog.Level
+
+	// StaleAvailabilityMaxAge lets the worker approve on the last-known
+	// availability, up to this old, when a lookup fails; 0 skips instead
+	StaleAvailabilityMaxAge time.Duration
+
+	// WebhookSubscribers receive a POST for booking approvals and
+	// rejections matching their statuses, signed with their secret when set
+	WebhookSubscribers          []WebhookSubscriber
+	DecisionWebhookTimeout      time.Duration
+	DecisionWebhookMaxRetries   int
+	DecisionWebhookRetryBackoff time.Duration
+
+	// NotifyPersist keeps decision webhook notifications that fail every
+	// in-memory retry in a queue file per subscriber under NotifyQueueDir,
+	// retried every NotifyRetryInterval until older than NotifyMaxAge
+	NotifyPersist       bool
+	NotifyQueueDir      string
+	NotifyQueueMaxSize  int
+	NotifyRetryInterval time.Duration
+	NotifyMaxAge        time.Duration
+
+	// KillSwitchFlagKey is the boolean flag that, when true, halts
+	// auto-approval regardless of the auto-approval flag. Empty disables it.
+	KillSwitchFlagKey string
+
+	// TierStatsWindow is how far back /api/stats/tiers counts approvals
+	// per tier, 0 to disable
+	TierStatsWindow time.Duration
+
+	// MaxManualApproveAge refuses manual approval of pending bookings
+	// created longer ago than this, unless overridden per request. 0
+	// disables the limit.
+	MaxManualApproveAge time.Duration
+
+	// WorkerMaxFetch caps how many pending bookings the worker fetches per
+	// tick, 0 for all
+	WorkerMaxFetch int
+
+	// Manual rejections may carry a reason code from the JSON list attached
+	// to RejectReasonCodesFlagKey's variant, cached for RejectReasonCodesTTL.
+	// RejectReasonCodes is used when the flag can't be evaluated.
+	RejectReasonCodesFlagKey string
+	RejectReasonCodes        []string
+	RejectReasonCodesTTL     time.Duration
+
+	// WorkerFetchPageSize makes the worker fetch pending bookings in pages
+	// of this size, processing the pages it got when a later one fails. 0
+	// fetches them in one request.
+	WorkerFetchPageSize int
+
+	// HotelMinGuests maps hotel IDs to the fewest guests they accept; the
+	// worker auto-rejects smaller bookings. Hotels not listed have no minimum.
+	HotelMinGuests map[string]int
+
+	// FliptFetchMode is how the Flipt SDK gets flag state: "streaming", or
+	// "polling" every FliptUpdateInterval where streaming isn't supported.
+	// A zero interval keeps the SDK's default.
+	FliptFetchMode      string
+	FliptUpdateInterval time.Duration
+
+	// BacklogAutoApproveThreshold, when positive, lets the worker auto-approve
+	// with the auto-approval flag off while more bookings than this are pending
+	BacklogAutoApproveThreshold int
+
+	// APIKeys maps API keys to the users and roles they authenticate as.
+	// When empty, authentication is disabled.
+	APIKeys map[string]Principal
+}
+
+func loadConfig() Config {
+	return Config{
+		FliptURL:                    getEnv("FLIPT_URL", "http://flipt:8080"),
+		FliptNamespace:              getEnv("FLIPT_NAMESPACE", "default"),
+		FliptEnvironment:            getEnv("FLIPT_ENVIRONMENT", "onoffinc"),
+		Port:                        getEnv("PORT", "8001"),
+		HotelServiceURL:             getEnv("HOTEL_SERVICE_URL", "http://hotel-service:8000"),
+		HotelServiceHealthPath:      getEnv("HOTEL_SERVICE_HEALTH_PATH", "/health"),
+		LogFormat:                   os.Getenv("LOG_FORMAT"),
+		LogFile:                     os.Getenv("LOG_FILE"),
+		RequestTimeout:              getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		TracerName:                  getEnv("OTEL_TRACER_NAME", "admin-service"),
+		MeterName:                   getEnv("OTEL_METER_NAME", "admin-service"),
+		InstrumentationVersion:      getEnv("OTEL_INSTRUMENTATION_VERSION", version),
+		OTLPRetryEnabled:            getEnvBool("OTEL_EXPORTER_OTLP_RETRY_ENABLED", true),
+		OTLPRetryInitialInterval:    getEnvDuration("OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL", 5*time.Second),
+		OTLPRetryMaxInterval:        getEnvDuration("OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL", 30*time.Second),
+		OTLPRetryMaxElapsedTime:     getEnvDuration("OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME", time.Minute),
+		OTLPBearerToken:             os.Getenv("OTEL_EXPORTER_OTLP_BEARER_TOKEN"),
+		OTELDebugStdout:             loadDebugStdout(),
+		OTELPropagators:             getEnvList("OTEL_PROPAGATORS", "tracecontext,baggage"),
+		MetricIncludeBookingID:      getEnvBool("METRIC_INCLUDE_BOOKING_ID", false),
+		HotelAvailabilityTimeout:    getEnvDuration("HOTEL_AVAILABILITY_TIMEOUT", 5*time.Second),
+		WebhookSecret:               os.Getenv("BOOKING_WEBHOOK_SECRET"),
+		HotelDenylist:               getEnvList("HOTEL_DENYLIST", ""),
+		HotelAllowlist:              getEnvList("HOTEL_ALLOWLIST", ""),
+		WorkerRateLimitBackoff:      getEnvDuration("WORKER_RATE_LIMIT_BACKOFF", 30*time.Second),
+		WorkerMaxSweepDuration:      getEnvDuration("WORKER_MAX_SWEEP_DURATION", 10*time.Second),
+		WorkerReadyTimeout:          getEnvDuration("WORKER_READY_TIMEOUT", time.Minute),
+		AuditLogSize:                getEnvInt("AUDIT_LOG_SIZE", 1000),
+		ProblemJSONErrors:           getEnvBool("PROBLEM_JSON_ERRORS", false),
+		EvaluationContext:           loadEvaluationContext(),
+		EvaluationContextHeaders:    getEnvMap("EVALUATION_CONTEXT_HEADERS", ""),
+		FliptRecordFile:             os.Getenv("FLIPT_RECORD_FILE"),
+		FliptReplayFile:             os.Getenv("FLIPT_REPLAY_FILE"),
+		BatchApproveConcurrency:     getEnvInt("BATCH_APPROVE_CONCURRENCY", 8),
+		BatchRejectConcurrency:      getEnvInt("BATCH_REJECT_CONCURRENCY", 4),
+		BulkCancelConcurrency:       getEnvInt("BULK_CANCEL_CONCURRENCY", 4),
+		TracingExcludePaths:         getEnvList("TRACING_EXCLUDE_PATHS", "/health,/metrics,/ready"),
+		TracingBuildAttributes:      getEnvBool("TRACING_BUILD_ATTRIBUTES", true),
+		ApprovalTierSLAs:            getEnvDurationMap("APPROVAL_TIER_SLAS", "standard:24h,premium:48h,vip:72h"),
+		ApprovalDefaultSLA:          getEnvDuration("APPROVAL_DEFAULT_SLA", 24*time.Hour),
+		HotelReadTimeout:            getEnvDuration("HOTEL_READ_TIMEOUT", 10*time.Second),
+		HotelWriteTimeout:           getEnvDuration("HOTEL_WRITE_TIMEOUT", 10*time.Second),
+		ShadowApprovalTierFlagKey:   os.Getenv("SHADOW_APPROVAL_TIER_FLAG_KEY"),
+		ApprovalTierFallbackFlags:   getEnvList("APPROVAL_TIER_FALLBACK_FLAGS", ""),
+		AsyncApproval:               getEnvBool("ASYNC_APPROVAL", false),
+		JobStoreSize:                getEnvInt("JOB_STORE_SIZE", 1000),
+		JobTTL:                      getEnvDuration("JOB_TTL", 15*time.Minute),
+		ApprovalKnownTiers:          getEnvList("APPROVAL_KNOWN_TIERS", "standard,premium,vip"),
+		ApprovalDefaultTier:         getEnv("APPROVAL_DEFAULT_TIER", "standard"),
+		ConfirmationPrefix:          loadConfirmationPrefix(),
+		ConfirmationTierPrefixes:    loadConfirmationTierPrefixes(),
+		WorkerPollInterval:          getEnvDuration("WORKER_POLL_INTERVAL", 10*time.Second),
+		WorkerTenants:               getEnvDurationMap("WORKER_TENANTS", ""),
+		WorkerTenantHotels:          getEnvMap("WORKER_TENANT_HOTELS", ""),
+		HTTPMaxIdleConns:            getEnvInt("HTTP_MAX_IDLE_CONNS", 100),
+		HTTPMaxIdleConnsPerHost:     getEnvInt("HTTP_MAX_IDLE_CONNS_PER_HOST", 10),
+		HTTPIdleConnTimeout:         getEnvDuration("HTTP_IDLE_CONN_TIMEOUT", 90*time.Second),
+		DecisionCacheTTL:            getEnvDuration("DECISION_CACHE_TTL", 30*time.Second),
+		WorkerShutdownSummary:       getEnvBool("WORKER_SHUTDOWN_SUMMARY", true),
+		FliptEvaluationTimeout:      getEnvDuration("FLIPT_EVALUATION_TIMEOUT", time.Second),
+		DefaultLanguage:             getEnv("DEFAULT_LANGUAGE", "en"),
+		MessageCatalog:              loadMessageCatalog(os.Getenv("MESSAGE_CATALOG_FILE")),
+		SlowRequestThreshold:        getEnvDuration("SLOW_REQUEST_THRESHOLD", 0),
+		ApprovalTierOverrides:       getEnvMap("APPROVAL_TIER_OVERRIDES", ""),
+		WorkerProcessingOrder:       getEnv("WORKER_PROCESSING_ORDER", orderAsReturned),
+		HotelMaxRetries:             getEnvInt("HOTEL_MAX_RETRIES", 0),
+		HotelRetryBackoff:           getEnvDuration("HOTEL_RETRY_BACKOFF", 100*time.Millisecond),
+		HotelRetryBudgetPercent:     getEnvInt("HOTEL_RETRY_BUDGET_PERCENT", 10),
+		MinAvailabilityBuffer:       getEnvInt("MIN_AVAILABILITY_BUFFER", 0),
+		WorkerTickSpanSampleRatio:   getEnvFloat("WORKER_TICK_SPAN_SAMPLE_RATIO", 0.1),
+		HotelRoutes:                 loadHotelRoutes(),
+		AdminFeatureFlagKey:         getEnv("ADMIN_FEATURE_FLAG_KEY", "new-approval-ui"),
+		EnrichmentConcurrency:       getEnvInt("ENRICHMENT_CONCURRENCY", 4),
+		EnrichmentLookupTimeout:     getEnvDuration("ENRICHMENT_LOOKUP_TIMEOUT", 500*time.Millisecond),
+		EnrichmentTimeout:           getEnvDuration("ENRICHMENT_TIMEOUT", 2*time.Second),
+		ChaosEnabled:                getEnvBool("CHAOS_ENABLED", false),
+		ChaosFailureRate:            getEnvFloat("CHAOS_FAILURE_RATE", 0),
+		ShutdownTimeout:             getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		ShutdownDrainPeriod:         getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 0),
+		ComponentStopTimeout:        getEnvDuration("COMPONENT_STOP_TIMEOUT", 5*time.Second),
+		ValueTierMediumFrom:         getEnvFloat("VALUE_TIER_MEDIUM_FROM", 200),
+		ValueTierHighFrom:           getEnvFloat("VALUE_TIER_HIGH_FROM", 500),
+		HotelMaxRedirects:           getEnvInt("HOTEL_MAX_REDIRECTS", 3),
+		AutoApprovalMinLeadDays:     getEnvInt("AUTO_APPROVAL_MIN_LEAD_DAYS", 0),
+		AutoApprovalMaxLeadDays:     getEnvInt("AUTO_APPROVAL_MAX_LEAD_DAYS", 0),
+		VerifyWrites:                getEnvBool("VERIFY_WRITES", false),
+		BookingCacheTTL:             getEnvDuration("BOOKING_CACHE_TTL", 0),
+		AccessLog:                   getEnvBool("ACCESS_LOG", false),
+		AccessLogLevel:              getEnvLevel("ACCESS_LOG_LEVEL", slog.LevelInfo),
+		StaleAvailabilityMaxAge:     getEnvDuration("STALE_AVAILABILITY_MAX_AGE", 0),
+		WebhookSubscribers:          loadWebhookSubscribers(os.Getenv("WEBHOOK_SUBSCRIBERS_FILE"), os.Getenv("WEBHOOK_URL"), os.Getenv("WEBHOOK_SECRET")),
+		DecisionWebhookTimeout:      getEnvDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		DecisionWebhookMaxRetries:   getEnvInt("WEBHOOK_MAX_RETRIES", 3),
+		DecisionWebhookRetryBackoff: getEnvDuration("WEBHOOK_RETRY_BACKOFF", time.Second),
+		NotifyPersist:               getEnvBool("NOTIFY_PERSIST", false),
+		NotifyQueueDir:              os.Getenv("NOTIFY_QUEUE_DIR"),
+		NotifyQueueMaxSize:          getEnvInt("NOTIFY_QUEUE_MAX_SIZE", 1000),
+		NotifyRetryInterval:         getEnvDuration("NOTIFY_RETRY_INTERVAL", 30*time.Second),
+		NotifyMaxAge:                getEnvDuration("NOTIFY_MAX_AGE", 24*time.Hour),
+		KillSwitchFlagKey:           getEnv("AUTO_APPROVAL_KILLSWITCH_FLAG_KEY", "auto-approval-killswitch"),
+		TierStatsWindow:             getEnvDuration("TIER_STATS_WINDOW", time.Hour),
+		MaxManualApproveAge:         getEnvDuration("MAX_MANUAL_APPROVE_AGE", 0),
+		WorkerMaxFetch:              getEnvInt("WORKER_MAX_FETCH", 0),
+		RejectReasonCodesFlagKey:    getEnv("REJECT_REASON_CODES_FLAG_KEY", "reject-reason-codes"),
+		RejectReasonCodes:           getEnvList("REJECT_REASON_CODES", "guest_request,invalid_details,payment_issue,suspected_fraud,duplicate"),
+		RejectReasonCodesTTL:        getEnvDuration("REJECT_REASON_CODES_TTL", 30*time.Second),
+		WorkerFetchPageSize:         getEnvInt("WORKER_FETCH_PAGE_SIZE", 0),
+		HotelMinGuests:              getEnvIntMap("HOTEL_MIN_GUESTS", ""),
+		FliptFetchMode:              loadFetchMode(),
+		FliptUpdateInterval:         getEnvDuration("FLIPT_UPDATE_INTERVAL", 0),
+		BacklogAutoApproveThreshold: getEnvInt("BACKLOG_AUTO_APPROVE_THRESHOLD", 0),
+		APIKeys:                     loadAPIKeys(),
+	}
+}
+
+// Confirmation numbers are always confirmationNumberLength characters: the
+// prefix, then random hex digits. Prefixes are capped so at least eight
+// random digits remain.
+const (
+	confirmationNumberLength    = 16
+	maxConfirmationPrefixLength = 8
+	defaultConfirmationPrefix   = "CNF-"
+)
+
+// loadConfirmationPrefix reads CONFIRMATION_PREFIX, falling back to the
+// default when it is too long.
+func loadConfirmationPrefix() string {
+	prefix := getEnv("CONFIRMATION_PREFIX", defaultConfirmationPrefix)
+	if len(prefix) > maxConfirmationPrefixLength {
+		log.Printf("Warning: CONFIRMATION_PREFIX %q is longer than %d characters, using %q", prefix, maxConfirmationPrefixLength, defaultConfirmationPrefix)
+		return defaultConfirmationPrefix
+	}
+	return prefix
+}
+
+// loadConfirmationTierPrefixes reads CONFIRMATION_TIER_PREFIXES, dropping
+// prefixes that are too long so those tiers use CONFIRMATION_PREFIX.
+func loadConfirmationTierPrefixes() map[string]string {
+	prefixes := getEnvMap("CONFIRMATION_TIER_PREFIXES", "")
+	for tier, prefix := range prefixes {
+		if len(prefix) > maxConfirmationPrefixLength {
+			log.Printf("Warning: ignoring CONFIRMATION_TIER_PREFIXES prefix %q for tier %s, longer than %d characters", prefix, tier, maxConfirmationPrefixLength)
+			delete(prefixes, tier)
+		}
+	}
+	return prefixes
+}
+
+// workerTenants returns the namespaces to run auto-approval workers for,
+// defaulting to the service's own namespace.
+func (c Config) workerTenants() map[string]time.Duration {
+	if len(c.WorkerTenants) > 0 {
+		return c.WorkerTenants
+	}
+	return map[string]time.Duration{c.FliptNamespace: c.WorkerPollInterval}
+}
+
+// tenantHotelScope returns the hotels whose bookings a tenant's worker
+// decides: those assigned to it in WorkerTenantHotels, or for the service's
+// own namespace every hotel not assigned to another tenant.
+func (c Config) tenantHotelScope(namespace string) hotelclient.HotelScope {
+	var scope hotelclient.HotelScope
+	for hotelID, tenant := range c.WorkerTenantHotels {
+		switch {
+		case namespace == c.FliptNamespace && tenant != namespace:
+			scope.ExcludeHotelIDs = append(scope.ExcludeHotelIDs, hotelID)
+		case namespace != c.FliptNamespace && tenant == namespace:
+			scope.HotelIDs = append(scope.HotelIDs, hotelID)
+		}
+	}
+	slices.Sort(scope.HotelIDs)
+	slices.Sort(scope.ExcludeHotelIDs)
+	return scope
+}
+
+// loadAPIKeys reads role-based keys from ADMIN_API_KEYS, falling back to a
+// single ADMIN_API_KEY with the admin role.
+func loadAPIKeys() map[string]Principal {
+	if keys := os.Getenv("ADMIN_API_KEYS"); keys != "" {
+		return parseAPIKeys(keys)
+	}
+	if key := os.Getenv("ADMIN_API_KEY"); key != "" {
+		return map[string]Principal{key: {User: keyUser(key), Role: RoleAdmin}}
+	}
+	return nil
+}
+
+// evaluationContextEnv maps environment variables to the evaluation context
+// keys they populate.
+var evaluationContextEnv = map[string]string{
+	"REGION":  "region",
+	"CLUSTER": "cluster",
+}
+
+func loadEvaluationContext() map[string]string {
+	evalCtx := map[string]string{}
+	for env, key := range evaluationContextEnv {
+		if v := os.Getenv(env); v != "" {
+			evalCtx[key] = v
+		}
+	}
+	return evalCtx
+}
+
+// hotelRouteEnv maps environment variables to the hotel client operations
+// whose path templates they override.
+var hotelRouteEnv = map[string]string{
+	"HOTEL_ROUTE_LIST_BOOKINGS":  hotelclient.RouteListBookings,
+	"HOTEL_ROUTE_GET_BOOKING":    hotelclient.RouteGetBooking,
+	"HOTEL_ROUTE_UPDATE_BOOKING": hotelclient.RouteUpdateBooking,
+	"HOTEL_ROUTE_AVAILABILITY":   hotelclient.RouteAvailability,
+}
+
+func loadHotelRoutes() map[string]string {
+	routes := map[string]string{}
+	for env, op := range hotelRouteEnv {
+		if v := os.Getenv(env); v != "" {
+			routes[op] = v
+		}
+	}
+	return routes
+}
+
+func getEnv(key, defaultValue string) string {
+	return cmp.Or(os.Getenv(key), defaultValue)
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+// getEnvList parses a comma-separated list, ignoring empty entries.
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for v := range strings.SplitSeq(getEnv(key, defaultValue), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// getEnvDurationMap parses comma-separated "key:duration" pairs, ignoring
+// invalid entries.
+func getEnvDurationMap(key, defaultValue string) map[string]time.Duration {
+	values := map[string]time.Duration{}
+	for _, entry := range getEnvList(key, defaultValue) {
+		k, v, ok := strings.Cut(entry, ":")
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if !ok || err != nil {
+			log.Printf("Ignoring invalid %s entry %q", key, entry)
+			continue
+		}
+		values[strings.TrimSpace(k)] = d
+	}
+	return values
+}
+
+// getEnvIntMap parses comma-separated "key:int" pairs, ignoring invalid
+// entries.
+func getEnvIntMap(key, defaultValue string) map[string]int {
+	values := map[string]int{}
+	for _, entry := range getEnvList(key, defaultValue) {
+		k, v, ok := strings.Cut(entry, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if !ok || err != nil {
+			log.Printf("Ignoring invalid %s entry %q", key, entry)
+			continue
+		}
+		values[strings.TrimSpace(k)] = n
+	}
+	return values
+}
+
+func getEnvMap(key, defaultValue string) map[string]string {
+	values := map[string]string{}
+	for _, entry := range getEnvList(key, defaultValue) {
+		k, v, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(k) == "" || strings.TrimSpace(v) == "" {
+			log.Printf("Ignoring invalid %s entry %q", key, entry)
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+func getEnvLevel(key string, defaultValue slog.Level) slog.Level {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		log.Printf("Warning: invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return level
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
+// loadDebugStdout parses OTEL_DEBUG_STDOUT, treating unknown values as off.
+func loadDebugStdout() string {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_DEBUG_STDOUT")))
+	switch mode {
+	case "", debugStdoutOff:
+		return debugStdoutOff
+	case debugStdoutOn, debugStdoutOnly:
+		return mode
+	default:
+		log.Printf("Warning: ignoring unknown OTEL_DEBUG_STDOUT %q, expected %q or %q", mode, debugStdoutOn, debugStdoutOnly)
+		return debugStdoutOff
+	}
+}
+
+// Values for FLIPT_FETCH_MODE, matching the Flipt SDK's fetch modes
+const (
+	fetchModeStreaming = "streaming"
+	fetchModePolling   = "polling"
+)
+
+// loadFetchMode parses FLIPT_FETCH_MODE, treating unknown values as streaming.
+func loadFetchMode() string {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("FLIPT_FETCH_MODE")))
+	switch mode {
+	case "":
+		return fetchModeStreaming
+	case fetchModeStreaming, fetchModePolling:
+		return mode
+	default:
+		log.Printf("Warning: ignoring unknown FLIPT_FETCH_MODE %q, expected %q or %q", mode, fetchModeStreaming, fetchModePolling)
+		return fetchModeStreaming
+	}
+}
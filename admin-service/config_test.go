@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTenantHotelScope(t *testing.T) {
+	cfg := Config{
+		FliptNamespace: "default",
+		WorkerTenantHotels: map[string]string{
+			"hotel_2": "partners",
+			"hotel_3": "partners",
+			"hotel_4": "resellers",
+			"hotel_5": "default",
+		},
+	}
+
+	own := cfg.tenantHotelScope("default")
+	if len(own.HotelIDs) != 0 {
+		t.Errorf("own namespace HotelIDs = %v, want none", own.HotelIDs)
+	}
+	if want := []string{"hotel_2", "hotel_3", "hotel_4"}; !slices.Equal(own.ExcludeHotelIDs, want) {
+		t.Errorf("own namespace ExcludeHotelIDs = %v, want %v", own.ExcludeHotelIDs, want)
+	}
+
+	partners := cfg.tenantHotelScope("partners")
+	if want := []string{"hotel_2", "hotel_3"}; !slices.Equal(partners.HotelIDs, want) {
+		t.Errorf("partners HotelIDs = %v, want %v", partners.HotelIDs, want)
+	}
+	if len(partners.ExcludeHotelIDs) != 0 {
+		t.Errorf("partners ExcludeHotelIDs = %v, want none", partners.ExcludeHotelIDs)
+	}
+
+	if unassigned := cfg.tenantHotelScope("other"); len(unassigned.HotelIDs) != 0 || len(unassigned.ExcludeHotelIDs) != 0 {
+		t.Errorf("unassigned tenant scope = %+v, want empty", unassigned)
+	}
+}
+
+func TestLoadConfirmationPrefixes(t *testing.T) {
+	t.Setenv("CONFIRMATION_PREFIX", "HTL-")
+	t.Setenv("CONFIRMATION_TIER_PREFIXES", "vip:VIP-,premium:TOOLONGPREFIX-")
+
+	if got := loadConfirmationPrefix(); got != "HTL-" {
+		t.Errorf("loadConfirmationPrefix() = %q, want HTL-", got)
+	}
+	prefixes := loadConfirmationTierPrefixes()
+	if got := prefixes["vip"]; got != "VIP-" {
+		t.Errorf("vip prefix = %q, want VIP-", got)
+	}
+	if got, ok := prefixes["premium"]; ok {
+		t.Errorf("overlong premium prefix %q kept", got)
+	}
+
+	t.Setenv("CONFIRMATION_PREFIX", "MUCHTOOLONG-")
+	if got := loadConfirmationPrefix(); got != defaultConfirmationPrefix {
+		t.Errorf("overlong CONFIRMATION_PREFIX gave %q, want %q", got, defaultConfirmationPrefix)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// bookingDecision holds the inputs the worker gathered for a booking so a
+// booking still pending on a later tick doesn't repeat the same Flipt and
+// hotel-service calls.
+type bookingDecision struct {
+	status       string
+	hotel        *hotelclient.HotelInfo
+	hotelFetched time.Time
+	tier         string
+	tierFetched  time.Time
+}
+
+// DecisionCache caches per-booking decision inputs for a TTL. An entry is
+// invalidated as soon as the booking's status differs from the status it was
+// cached under. Availability is kept for up to staleMaxAge even past the
+// TTL, for the worker to fall back on when a lookup fails.
+type DecisionCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	staleMaxAge time.Duration
+	entries     map[string]bookingDecision
+}
+
+func NewDecisionCache(ttl, staleMaxAge time.Duration) *DecisionCache {
+	return &DecisionCache{ttl: ttl, staleMaxAge: staleMaxAge, entries: map[string]bookingDecision{}}
+}
+
+func (c *DecisionCache) enabled() bool {
+	return c.ttl > 0
+}
+
+// Get returns the decision inputs cached for a booking within the TTL.
+// Inputs gathered longer ago are left out.
+func (c *DecisionCache) Get(booking *hotelclient.Booking) (bookingDecision, bool) {
+	if !c.enabled() {
+		return bookingDecision{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.lookup(booking)
+	if !ok {
+		return bookingDecision{}, false
+	}
+	now := timeNow()
+	if now.Sub(d.hotelFetched) >= c.ttl {
+		d.hotel = nil
+	}
+	if now.Sub(d.tierFetched) >= c.ttl {
+		d.tier = ""
+	}
+	return d, d.hotel != nil || d.tier != ""
+}
+
+// Stale returns a copy of the availability last fetched for a booking and
+// how old it is, if it is at most staleMaxAge old.
+func (c *DecisionCache) Stale(booking *hotelclient.Booking) (*hotelclient.HotelInfo, time.Duration, bool) {
+	if c.staleMaxAge <= 0 {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.lookup(booking)
+	if !ok || d.hotel == nil {
+		return nil, 0, false
+	}
+	age := timeNow().Sub(d.hotelFetched)
+	if age > c.staleMaxAge {
+		return nil, 0, false
+	}
+	hotel := *d.hotel
+	return &hotel, age, true
+}
+
+// SetHotel caches availability just fetched for a booking.
+func (c *DecisionCache) SetHotel(booking *hotelclient.Booking, hotel *hotelclient.HotelInfo) {
+	c.update(booking, func(d *bookingDecision, now time.Time) {
+		d.hotel, d.hotelFetched = hotel, now
+	})
+}
+
+// SetTier caches the approval tier just evaluated for a booking.
+func (c *DecisionCache) SetTier(booking *hotelclient.Booking, tier string) {
+	if !c.enabled() {
+		return
+	}
+	c.update(booking, func(d *bookingDecision, now time.Time) {
+		d.tier, d.tierFetched = tier, now
+	})
+}
+
+// Invalidate drops a booking's cached inputs, e.g. once it is decided.
+func (c *DecisionCache) Invalidate(bookingID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, bookingID)
+}
+
+// retention is how long an entry is kept after its inputs were gathered.
+func (c *DecisionCache) retention() time.Duration {
+	return max(c.ttl, c.staleMaxAge)
+}
+
+// lookup returns a booking's entry, dropping it when the booking's status
+// changed since. Callers hold c.mu.
+func (c *DecisionCache) lookup(booking *hotelclient.Booking) (bookingDecision, bool) {
+	d, ok := c.entries[booking.BookingID]
+	if !ok {
+		return bookingDecision{}, false
+	}
+	if d.status != booking.Status {
+		delete(c.entries, booking.BookingID)
+		return bookingDecision{}, false
+	}
+	return d, true
+}
+
+// update applies fn to the booking's entry, starting a new one when none is
+// cached, and drops entries past retention.
+func (c *DecisionCache) update(booking *hotelclient.Booking, fn func(d *bookingDecision, now time.Time)) {
+	retention := c.retention()
+	if retention <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := timeNow()
+	for id, d := range c.entries {
+		gathered := d.hotelFetched
+		if d.tierFetched.After(gathered) {
+			gathered = d.tierFetched
+		}
+		if now.Sub(gathered) > retention {
+			delete(c.entries, id)
+		}
+	}
+
+	d, ok := c.lookup(booking)
+	if !ok {
+		d = bookingDecision{status: booking.Status}
+	}
+	fn(&d, now)
+	c.entries[booking.BookingID] = d
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+func TestDecisionCacheFreshAndStaleAvailability(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, now)
+	cache := NewDecisionCache(30*time.Second, 2*time.Minute)
+	booking := pendingBooking("b1", "hotel_1")
+	cache.SetHotel(&booking, &hotelclient.HotelInfo{ID: "hotel_1", AvailableRooms: 3})
+
+	for _, tc := range []struct {
+		after        time.Duration
+		fresh, stale bool
+	}{
+		{10 * time.Second, true, true},
+		{time.Minute, false, true},
+		{2 * time.Minute, false, true},
+		{3 * time.Minute, false, false},
+	} {
+		setClock(t, now.Add(tc.after))
+
+		d, ok := cache.Get(&booking)
+		if fresh := ok && d.hotel != nil; fresh != tc.fresh {
+			t.Errorf("after %s: fresh = %t, want %t", tc.after, fresh, tc.fresh)
+		}
+		hotel, age, ok := cache.Stale(&booking)
+		if ok != tc.stale {
+			t.Errorf("after %s: stale = %t, want %t", tc.after, ok, tc.stale)
+		}
+		if ok && (hotel.AvailableRooms != 3 || age != tc.after) {
+			t.Errorf("after %s: stale availability %+v from %s ago", tc.after, hotel, age)
+		}
+	}
+}
+
+func TestDecisionCacheDropsEntriesWhenStatusChanges(t *testing.T) {
+	setClock(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := NewDecisionCache(30*time.Second, 2*time.Minute)
+	booking := pendingBooking("b1", "hotel_1")
+	cache.SetHotel(&booking, &hotelclient.HotelInfo{ID: "hotel_1", AvailableRooms: 3})
+	cache.SetTier(&booking, "standard")
+
+	booking.Status = "confirmed"
+	if _, ok := cache.Get(&booking); ok {
+		t.Error("Get returned inputs cached under another status")
+	}
+	if _, _, ok := cache.Stale(&booking); ok {
+		t.Error("Stale returned availability cached under another status")
+	}
+}
+
+func TestDecisionCacheStaleAvailabilityWithoutTTL(t *testing.T) {
+	setClock(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := NewDecisionCache(0, time.Minute)
+	booking := pendingBooking("b1", "hotel_1")
+	cache.SetHotel(&booking, &hotelclient.HotelInfo{ID: "hotel_1", AvailableRooms: 3})
+	cache.SetTier(&booking, "standard")
+
+	if _, ok := cache.Get(&booking); ok {
+		t.Error("Get returned inputs with the decision cache disabled")
+	}
+	if _, _, ok := cache.Stale(&booking); !ok {
+		t.Error("Stale lost availability with the decision cache disabled")
+	}
+}
+
+func TestWorkerFallsBackOnStaleAvailability(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		after  time.Duration
+		status string
+	}{
+		{"fresh cache skips the lookup", 10 * time.Second, "confirmed"},
+		{"stale cache covers a failed lookup", time.Minute, "confirmed"},
+		{"too stale to approve", 3 * time.Minute, "pending"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			setClock(t, now)
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", true)
+			evaluator.setBoolean("auto-approval-killswitch", false)
+			evaluator.setBoolean("require-manual-review", false)
+			evaluator.setVariant("approval-tier", "standard")
+			svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+				cfg.DecisionCacheTTL = 30 * time.Second
+				cfg.StaleAvailabilityMaxAge = 2 * time.Minute
+			})
+
+			// Availability seen earlier, e.g. while listing bookings
+			booking := hotel.booking("b1")
+			if _, err := svc.getHotelAvailability(context.Background(), &booking); err != nil {
+				t.Fatal(err)
+			}
+			hotel.mu.Lock()
+			hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+				if strings.HasSuffix(r.URL.Path, "/availability") {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+					return true
+				}
+				return false
+			}
+			hotel.mu.Unlock()
+			setClock(t, now.Add(tc.after))
+
+			NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+			if status := hotel.booking("b1").Status; status != tc.status {
+				t.Errorf("booking status = %s, want %s", status, tc.status)
+			}
+			lookups := 0
+			for _, uri := range hotel.requested(http.MethodGet) {
+				if strings.HasPrefix(uri, "/api/hotels/") {
+					lookups++
+				}
+			}
+			if fresh := tc.after < 30*time.Second; fresh && lookups != 1 {
+				t.Errorf("availability looked up %d times, want only the first with a fresh cache", lookups)
+			}
+		})
+	}
+}
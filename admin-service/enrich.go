@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// ListedBooking is a booking as returned by the API, labeled with its local
+// value tier and optionally enriched with its hotel's current availability
+// for the booking's dates.
+type ListedBooking struct {
+	hotelclient.Booking
+	ValueTier      string `json:"value_tier"`
+	AvailableRooms *int   `json:"available_rooms,omitempty"`
+}
+
+// enrichBookings looks up availability for each booking, at most
+// EnrichmentConcurrency at a time. Each lookup is bounded by
+// EnrichmentLookupTimeout and the whole enrichment by EnrichmentTimeout;
+// bookings whose lookup fails or times out are returned un-enriched, so one
+// slow hotel can't hold up the list.
+func (s *AdminService) enrichBookings(ctx context.Context, bookings []hotelclient.Booking) []ListedBooking {
+	ctx, span := tracer.Start(ctx, "enrich_bookings")
+	defer span.End()
+
+	if s.cfg.EnrichmentTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.EnrichmentTimeout)
+		defer cancel()
+	}
+
+	listed := make([]ListedBooking, len(bookings))
+	var enriched, timeouts atomic.Int64
+	var g errgroup.Group
+	g.SetLimit(max(s.cfg.EnrichmentConcurrency, 1))
+	for i, booking := range bookings {
+		listed[i].Booking = booking
+		g.Go(func() error {
+			lookupCtx := ctx
+			if s.cfg.EnrichmentLookupTimeout > 0 {
+				var cancel context.CancelFunc
+				lookupCtx, cancel = context.WithTimeout(ctx, s.cfg.EnrichmentLookupTimeout)
+				defer cancel()
+			}
+
+			hotel, err := s.hotelClient.GetHotelAvailability(lookupCtx, booking.HotelID, booking.Checkin, booking.Checkout, booking.Guests)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					timeouts.Add(1)
+					metricsFromContext(ctx).Add(ctx, s.enrichmentTimeoutCounter, 1,
+						attribute.String("hotel_id", booking.HotelID),
+					)
+				} else {
+					log.Printf("Error enriching booking %s: %v", booking.BookingID, err)
+				}
+				return nil
+			}
+			listed[i].AvailableRooms = &hotel.AvailableRooms
+			enriched.Add(1)
+			return nil
+		})
+	}
+	g.Wait()
+
+	span.SetAttributes(
+		attribute.Int("bookings", len(bookings)),
+		attribute.Int64("enriched", enriched.Load()),
+		attribute.Int64("timeouts", timeouts.Load()),
+	)
+	return listed
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "go.flipt.io/flipt-client"
+)
+
+// Evaluator evaluates Flipt flags. It is satisfied by *sdk.Client and by the
+// recording and replay evaluators below.
+type Evaluator interface {
+	EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error)
+	EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error)
+}
+
+var _ Evaluator = (*sdk.Client)(nil)
+
+// flagNotFound reports whether an evaluation failed because the flag doesn't
+// exist in the namespace. The SDK only says so in the error message.
+func flagNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "failed to get flag information")
+}
+
+// DeadlineEvaluator bounds every evaluation by the caller's deadline and an
+// optional per-evaluation timeout. The SDK evaluates locally without
+// observing the context, so an evaluation still running when the deadline
+// passes is abandoned and context.DeadlineExceeded returned.
+type DeadlineEvaluator struct {
+	next    Evaluator
+	timeout time.Duration
+}
+
+func NewDeadlineEvaluator(next Evaluator, timeout time.Duration) *DeadlineEvaluator {
+	return &DeadlineEvaluator{next: next, timeout: timeout}
+}
+
+func (e *DeadlineEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	return evaluateWithDeadline(ctx, e.timeout, func(ctx context.Context) (*sdk.BooleanEvaluationResponse, error) {
+		return e.next.EvaluateBoolean(ctx, req)
+	})
+}
+
+func (e *DeadlineEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	return evaluateWithDeadline(ctx, e.timeout, func(ctx context.Context) (*sdk.VariantEvaluationResponse, error) {
+		return e.next.EvaluateVariant(ctx, req)
+	})
+}
+
+func evaluateWithDeadline[T any](ctx context.Context, timeout time.Duration, evaluate func(context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return evaluate(ctx)
+	}
+
+	type result struct {
+		resp T
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := evaluate(ctx)
+		done <- result{resp, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+const (
+	evaluationTypeBoolean = "boolean"
+	evaluationTypeVariant = "variant"
+)
+
+// evaluationRecord is a single recorded evaluation, stored one per line as
+// JSON.
+type evaluationRecord struct {
+	Type              string            `json:"type"`
+	Namespace         string            `json:"namespace,omitempty"`
+	FlagKey           string            `json:"flag_key"`
+	EntityID          string            `json:"entity_id"`
+	Context           map[string]string `json:"context,omitempty"`
+	Enabled           bool              `json:"enabled,omitempty"`
+	Match             bool              `json:"match,omitempty"`
+	VariantKey        string            `json:"variant_key,omitempty"`
+	VariantAttachment string            `json:"variant_attachment,omitempty"`
+	SegmentKeys       []string          `json:"segment_keys,omitempty"`
+	Reason            string            `json:"reason,omitempty"`
+}
+
+// key identifies the evaluation a record answers. encoding/json sorts map
+// keys, so equal contexts produce equal keys; nil and empty contexts match.
+func (r evaluationRecord) key() string {
+	evalCtx := []byte("{}")
+	if len(r.Context) > 0 {
+		evalCtx, _ = json.Marshal(r.Context)
+	}
+	return r.Type + "\x00" + r.Namespace + "\x00" + r.FlagKey + "\x00" + r.EntityID + "\x00" + string(evalCtx)
+}
+
+func newEvaluationRecord(typ, namespace string, req *sdk.EvaluationRequest) evaluationRecord {
+	return evaluationRecord{
+		Type:      typ,
+		Namespace: namespace,
+		FlagKey:   req.FlagKey,
+		EntityID:  req.EntityID,
+		Context:   req.Context,
+	}
+}
+
+// recordingFile is the file RecordingEvaluators of every namespace append to
+type recordingFile struct {
+	mu     sync.Mutex
+	f      *os.File
+	enc    *json.Encoder
+	closed bool
+}
+
+// RecordingEvaluator delegates to another Evaluator and appends every
+// successful evaluation to a file that ReplayEvaluator can load.
+type RecordingEvaluator struct {
+	next      Evaluator
+	namespace string
+	file      *recordingFile
+}
+
+// NewRecordingEvaluator records the evaluations of next, a client for
+// namespace, to the file at path.
+func NewRecordingEvaluator(next Evaluator, namespace, path string) (*RecordingEvaluator, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording file: %w", err)
+	}
+	return &RecordingEvaluator{next: next, namespace: namespace, file: &recordingFile{f: f, enc: json.NewEncoder(f)}}, nil
+}
+
+// Tenant returns an evaluator recording the evaluations of next, a client
+// for another namespace, to the same file.
+func (e *RecordingEvaluator) Tenant(next Evaluator, namespace string) *RecordingEvaluator {
+	return &RecordingEvaluator{next: next, namespace: namespace, file: e.file}
+}
+
+func (e *RecordingEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	resp, err := e.next.EvaluateBoolean(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	record := newEvaluationRecord(evaluationTypeBoolean, e.namespace, req)
+	record.Enabled = resp.Enabled
+	record.Reason = resp.Reason
+	e.record(record)
+	return resp, nil
+}
+
+func (e *RecordingEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	resp, err := e.next.EvaluateVariant(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	record := newEvaluationRecord(evaluationTypeVariant, e.namespace, req)
+	record.Match = resp.Match
+	record.VariantKey = resp.VariantKey
+	record.VariantAttachment = resp.VariantAttachment
+	record.SegmentKeys = resp.SegmentKeys
+	record.Reason = resp.Reason
+	e.record(record)
+	return resp, nil
+}
+
+// Close closes the recording file shared with every tenant's evaluator.
+// Evaluations made afterwards are no longer recorded.
+func (e *RecordingEvaluator) Close() error {
+	e.file.mu.Lock()
+	defer e.file.mu.Unlock()
+
+	if e.file.closed {
+		return nil
+	}
+	e.file.closed = true
+	return e.file.f.Close()
+}
+
+func (e *RecordingEvaluator) Name() string {
+	return "flipt evaluation recorder"
+}
+
+func (e *RecordingEvaluator) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop closes the recording file.
+func (e *RecordingEvaluator) Stop(ctx context.Context) error {
+	return e.Close()
+}
+
+func (e *RecordingEvaluator) record(record evaluationRecord) {
+	e.file.mu.Lock()
+	defer e.file.mu.Unlock()
+
+	if e.file.closed {
+		return
+	}
+	if err := e.file.enc.Encode(record); err != nil {
+		log.Printf("Error recording evaluation of %s: %v", record.FlagKey, err)
+	}
+}
+
+// ReplayEvaluator answers evaluations from a file written by
+// RecordingEvaluator, matching on namespace, flag key, entity and context.
+// When the same evaluation was recorded more than once the latest result
+// wins.
+type ReplayEvaluator struct {
+	namespace string
+	records   map[string]evaluationRecord
+}
+
+// NewReplayEvaluator loads the file at path to answer evaluations in
+// namespace. Records without a namespace, from files recorded before
+// tenants were recorded separately, belong to namespace.
+func NewReplayEvaluator(path, namespace string) (*ReplayEvaluator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening replay file: %w", err)
+	}
+	defer f.Close()
+
+	records := map[string]evaluationRecord{}
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var record evaluationRecord
+		if err := dec.Decode(&record); err != nil {
+			return nil, fmt.Errorf("decoding replay file: %w", err)
+		}
+		if record.Namespace == "" {
+			record.Namespace = namespace
+		}
+		records[record.key()] = record
+	}
+	return &ReplayEvaluator{namespace: namespace, records: records}, nil
+}
+
+// Tenant returns an evaluator answering another namespace's evaluations
+// from the same file.
+func (e *ReplayEvaluator) Tenant(namespace string) *ReplayEvaluator {
+	return &ReplayEvaluator{namespace: namespace, records: e.records}
+}
+
+func (e *ReplayEvaluator) lookup(typ string, req *sdk.EvaluationRequest) (evaluationRecord, error) {
+	record, ok := e.records[newEvaluationRecord(typ, e.namespace, req).key()]
+	if !ok {
+		return evaluationRecord{}, fmt.Errorf("no recorded %s evaluation of %s for entity %s in namespace %s", typ, req.FlagKey, req.EntityID, e.namespace)
+	}
+	return record, nil
+}
+
+func (e *ReplayEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	record, err := e.lookup(evaluationTypeBoolean, req)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.BooleanEvaluationResponse{
+		FlagKey: record.FlagKey,
+		Enabled: record.Enabled,
+		Reason:  record.Reason,
+	}, nil
+}
+
+func (e *ReplayEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	record, err := e.lookup(evaluationTypeVariant, req)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.VariantEvaluationResponse{
+		FlagKey:           record.FlagKey,
+		Match:             record.Match,
+		VariantKey:        record.VariantKey,
+		VariantAttachment: record.VariantAttachment,
+		SegmentKeys:       record.SegmentKeys,
+		Reason:            record.Reason,
+	}, nil
+}
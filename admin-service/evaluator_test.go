@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "go.flipt.io/flipt-client"
+)
+
+func TestReplayAnswersEachNamespaceFromSharedRecording(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "evaluations.jsonl")
+
+	defaultFlags, partnerFlags := newFakeEvaluator(), newFakeEvaluator()
+	defaultFlags.setBoolean("auto-approval", true)
+	partnerFlags.setBoolean("auto-approval", false)
+
+	recorder, err := NewRecordingEvaluator(defaultFlags, "default", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Close()
+	req := &sdk.EvaluationRequest{FlagKey: "auto-approval", EntityID: "worker"}
+	if _, err := recorder.EvaluateBoolean(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Tenant(partnerFlags, "partners").EvaluateBoolean(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := NewReplayEvaluator(path, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for namespace, want := range map[string]bool{"default": true, "partners": false} {
+		resp, err := replay.Tenant(namespace).EvaluateBoolean(ctx, req)
+		if err != nil {
+			t.Fatalf("%s: %v", namespace, err)
+		}
+		if resp.Enabled != want {
+			t.Errorf("%s: Enabled = %v, want %v", namespace, resp.Enabled, want)
+		}
+	}
+
+	if _, err := replay.Tenant("unrecorded").EvaluateBoolean(ctx, req); err == nil {
+		t.Error("replaying an unrecorded namespace succeeded, want error")
+	}
+}
+
+func TestRecordingEvaluatorClosesFileOnShutdown(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "evaluations.jsonl")
+	flags := newFakeEvaluator()
+	flags.setBoolean("auto-approval", true)
+
+	recorder, err := NewRecordingEvaluator(flags, "default", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partners := recorder.Tenant(flags, "partners")
+	supervisor := NewSupervisor(time.Second)
+	supervisor.Register(recorder)
+	if err := supervisor.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &sdk.EvaluationRequest{FlagKey: "auto-approval", EntityID: "worker"}
+	recorder.EvaluateBoolean(ctx, req)
+	if err := supervisor.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.file.f.Stat(); err == nil {
+		t.Error("recording file still open after shutdown")
+	}
+
+	// Evaluations still answer once the file is closed, without recording
+	if resp, err := partners.EvaluateBoolean(ctx, req); err != nil || !resp.Enabled {
+		t.Errorf("evaluation after close = %+v, %v", resp, err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		lines++
+	}
+	if lines != 1 {
+		t.Errorf("recorded %d evaluations, want 1", lines)
+	}
+}
+
+// slowEvaluator answers like its fakeEvaluator after a delay, ignoring the
+// context the way the SDK's local evaluation does
+type slowEvaluator struct {
+	*fakeEvaluator
+	delay time.Duration
+}
+
+func (e slowEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	time.Sleep(e.delay)
+	return e.fakeEvaluator.EvaluateBoolean(ctx, req)
+}
+
+func (e slowEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	time.Sleep(e.delay)
+	return e.fakeEvaluator.EvaluateVariant(ctx, req)
+}
+
+func TestDeadlineEvaluatorBoundsSlowEvaluations(t *testing.T) {
+	flags := newFakeEvaluator()
+	flags.setBoolean("auto-approval", true)
+	flags.setVariant("approval-tier", "vip")
+	slow := slowEvaluator{flags, time.Second}
+	req := &sdk.EvaluationRequest{FlagKey: "auto-approval", EntityID: "worker"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := NewDeadlineEvaluator(slow, 0).EvaluateBoolean(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("evaluation past the request deadline = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("evaluation returned after %s, want it bounded by the 20ms deadline", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := NewDeadlineEvaluator(slow, 20*time.Millisecond).EvaluateBoolean(context.Background(), req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("evaluation past the evaluation timeout = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("evaluation returned after %s, want it bounded by the 20ms timeout", elapsed)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := NewDeadlineEvaluator(slowEvaluator{flags, 0}, time.Second).EvaluateBoolean(ctx, req)
+	if err != nil || !resp.Enabled {
+		t.Errorf("fast evaluation = %+v, %v, want enabled", resp, err)
+	}
+}
+
+func TestApprovalTierFallsBackWhenEvaluationTimesOut(t *testing.T) {
+	flags := newFakeEvaluator()
+	flags.setVariant("approval-tier", "vip")
+	svc := newTestService(t, NewDeadlineEvaluator(slowEvaluator{flags, time.Second}, 20*time.Millisecond), newFakeHotelService(t), func(cfg *Config) {
+		cfg.ApprovalDefaultTier = "standard"
+	})
+	booking := pendingBooking("b1", "hotel_1")
+
+	tier, err := svc.evaluateApprovalRules(context.Background(), &booking)
+	if err != nil || tier != "standard" {
+		t.Errorf("tier after an evaluation timeout = %q, %v, want the default standard", tier, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := svc.evaluateApprovalRules(ctx, &booking); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("tier past the request deadline = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// eventBufferSize is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const eventBufferSize = 64
+
+// BookingEvent is published to stream subscribers when a booking is decided
+type BookingEvent struct {
+	BookingID    string    `json:"booking_id"`
+	HotelID      string    `json:"hotel_id"`
+	Status       string    `json:"status"`
+	Tier         string    `json:"tier,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	AutoApproval bool      `json:"auto_approval"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// EventBus fans booking events out to stream subscribers. Publishing never
+// blocks: a subscriber that isn't keeping up misses events.
+type EventBus struct {
+	mu     sync.Mutex
+	subs   map[chan BookingEvent]struct{}
+	closed bool
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subs: map[chan BookingEvent]struct{}{}}
+}
+
+// Subscribe registers a subscriber. The returned channel is closed when the
+// bus is closed; unsubscribe must be called once the subscriber is done.
+func (b *EventBus) Subscribe() (events <-chan BookingEvent, unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan BookingEvent, eventBufferSize)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends an event to every subscriber.
+func (b *EventBus) Publish(event BookingEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close unsubscribes every subscriber by closing its channel, letting stream
+// handlers end their responses cleanly. It is registered to run when the
+// HTTP server shuts down.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// GetApiBookingsStream streams booking decisions as server-sent events. On
+// server shutdown a final close event is sent so clients can tell a deploy
+// from a dropped connection and reconnect with backoff.
+func (s *AdminService) GetApiBookingsStream(w http.ResponseWriter, r *http.Request) {
+	ctx, span := startHandlerSpan(r, "stream_bookings")
+	defer span.End()
+
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	sent := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("events_sent", sent))
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				span.SetAttributes(attribute.Bool("closed_by_server", true))
+				fmt.Fprint(w, "event: close\ndata: {}\n\n")
+				rc.Flush()
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Error encoding booking event: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: booking\ndata: %s\n\n", data)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			sent++
+		}
+	}
+}
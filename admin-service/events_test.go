@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+)
+
+// nextEvent reads a server-sent event's lines up to the blank line ending it
+func nextEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v (read %q)", err, lines)
+		}
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestBookingStreamClosesCleanlyOnShutdown(t *testing.T) {
+	svc := newTestService(t, newFakeEvaluator(), newFakeHotelService(t), nil)
+	srv := httptest.NewUnstartedServer(api.HandlerFromMux(svc, http.NewServeMux()))
+	srv.Config.RegisterOnShutdown(svc.events.Close)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL + "/api/bookings/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	body := bufio.NewReader(resp.Body)
+
+	svc.events.Publish(BookingEvent{BookingID: "b1", HotelID: "hotel_1", Status: "confirmed"})
+	if event := nextEvent(t, body); !strings.HasPrefix(event, "event: booking\n") || !strings.Contains(event, `"booking_id":"b1"`) {
+		t.Errorf("first event = %q, want the b1 booking event", event)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- srv.Config.Shutdown(ctx) }()
+
+	if event := nextEvent(t, body); event != "event: close\ndata: {}\n" {
+		t.Errorf("event on shutdown = %q, want the close event", event)
+	}
+	if rest, err := io.ReadAll(body); err != nil || len(rest) > 0 {
+		t.Errorf("stream after the close event = %q, %v, want a clean end", rest, err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("shutdown waiting on the stream: %v", err)
+	}
+
+	svc.events.mu.Lock()
+	subscribers := len(svc.events.subs)
+	svc.events.mu.Unlock()
+	if subscribers != 0 {
+		t.Errorf("%d subscribers left after shutdown, want none", subscribers)
+	}
+
+	events, unsubscribe := svc.events.Subscribe()
+	defer unsubscribe()
+	if _, ok := <-events; ok {
+		t.Error("subscribing after shutdown received an event, want a closed channel")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+func TestExportOutlivesServerWriteTimeout(t *testing.T) {
+	var bookings []hotelclient.Booking
+	for i := range 2 * exportPageSize {
+		bookings = append(bookings, pendingBooking(fmt.Sprintf("b%d", i), "hotel_1"))
+	}
+	hotel := newFakeHotelService(t, bookings...)
+	// Each page takes longer than the server's write timeout
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		time.Sleep(150 * time.Millisecond)
+		return false
+	}
+	svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+	srv := httptest.NewUnstartedServer(api.HandlerFromMux(svc, http.NewServeMux()))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL + "/api/bookings/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("export = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("reading export after %d rows: %v", len(rows), err)
+	}
+	if want := len(bookings) + 1; len(rows) != want {
+		t.Errorf("export has %d rows, want %d", len(rows), want)
+	}
+}
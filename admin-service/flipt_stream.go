@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// fliptStreamPathSuffix identifies the SDK's streaming snapshot requests
+const fliptStreamPathSuffix = "/stream"
+
+// streamWatcher observes the Flipt SDK's streaming connection from the HTTP
+// transport. The SDK reconnects on its own and exposes no connection events,
+// so every streaming request after the first is treated as a reconnect
+// attempt and timed against when the previous connection ended.
+type streamWatcher struct {
+	next       http.RoundTripper
+	namespace  string
+	reconnects metric.Int64Counter
+
+	mu           sync.Mutex
+	connected    bool
+	everStarted  bool
+	attempts     int
+	disconnected time.Time
+}
+
+func newStreamWatcher(next http.RoundTripper, namespace string) *streamWatcher {
+	reconnects, _ := meter.Int64Counter(
+		"flipt_stream_reconnects_total",
+		metric.WithDescription("Total number of Flipt streaming reconnection attempts, by result"),
+	)
+
+	return &streamWatcher{
+		next:       next,
+		namespace:  namespace,
+		reconnects: reconnects,
+	}
+}
+
+func (s *streamWatcher) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, fliptStreamPathSuffix) {
+		return s.next.RoundTrip(req)
+	}
+
+	s.mu.Lock()
+	reconnect := s.everStarted
+	s.everStarted = true
+	if reconnect {
+		s.attempts++
+		log.Printf("Flipt stream for namespace %s reconnecting (attempt %d, %s since disconnect)",
+			s.namespace, s.attempts, timeNow().Sub(s.disconnected).Round(time.Millisecond))
+	}
+	s.mu.Unlock()
+
+	resp, err := s.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		s.failed(req.Context(), reconnect, err, resp)
+		return resp, err
+	}
+
+	s.connect(req.Context(), reconnect)
+	resp.Body = &watchedBody{ReadCloser: resp.Body, onEnd: s.disconnect}
+	return resp, nil
+}
+
+// failed records a streaming request that didn't establish a connection
+func (s *streamWatcher) failed(ctx context.Context, reconnect bool, err error, resp *http.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.disconnected.IsZero() {
+		s.disconnected = timeNow()
+	}
+	if !reconnect {
+		return
+	}
+
+	s.reconnects.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("flipt_namespace", s.namespace),
+		attribute.String("result", "failure"),
+	))
+	if err != nil {
+		log.Printf("Warning: Flipt stream for namespace %s failed to reconnect: %v", s.namespace, err)
+	} else {
+		log.Printf("Warning: Flipt stream for namespace %s failed to reconnect: status %d", s.namespace, resp.StatusCode)
+	}
+}
+
+// connect records an established streaming connection
+func (s *streamWatcher) connect(ctx context.Context, reconnect bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected = true
+	if reconnect {
+		s.reconnects.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("flipt_namespace", s.namespace),
+			attribute.String("result", "success"),
+		))
+		log.Printf("Flipt stream for namespace %s reconnected after %d attempt(s), down for %s",
+			s.namespace, s.attempts, timeNow().Sub(s.disconnected).Round(time.Millisecond))
+	}
+	s.attempts = 0
+	s.disconnected = time.Time{}
+}
+
+// disconnect records the end of an established streaming connection
+func (s *streamWatcher) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.connected {
+		return
+	}
+	s.connected = false
+	s.disconnected = timeNow()
+	log.Printf("Flipt stream for namespace %s disconnected", s.namespace)
+}
+
+// watchedBody calls onEnd once, when the stream ends or is closed
+type watchedBody struct {
+	io.ReadCloser
+	onEnd func()
+	once  sync.Once
+}
+
+func (b *watchedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.onEnd)
+	}
+	return n, err
+}
+
+func (b *watchedBody) Close() error {
+	b.once.Do(b.onEnd)
+	return b.ReadCloser.Close()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"sync"
 
 	sdk "go.flipt.io/flipt-client"
 	"go.opentelemetry.io/otel/attribute"
@@ -10,6 +11,43 @@ import (
 
 var _ sdk.Hook = (*FliptHook)(nil)
 
+// FlagDiagnostic is the outcome of one Flipt evaluation as seen by the hook
+type FlagDiagnostic struct {
+	FlagKey  string `json:"flag_key"`
+	FlagType string `json:"flag_type"`
+	Value    string `json:"value"`
+	Reason   string `json:"reason"`
+}
+
+// EvaluationDiagnostics collects the evaluations made on behalf of a single
+// request. The hook may run on another goroutine than the handler, e.g. for
+// abandoned or shadow evaluations, so access is synchronized.
+type EvaluationDiagnostics struct {
+	mu    sync.Mutex
+	flags []FlagDiagnostic
+}
+
+type diagnosticsContextKey struct{}
+
+// withEvaluationDiagnostics returns a context whose Flipt evaluations are
+// recorded in a new, request-scoped EvaluationDiagnostics.
+func withEvaluationDiagnostics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, diagnosticsContextKey{}, &EvaluationDiagnostics{})
+}
+
+// evaluationDiagnostics returns the evaluations recorded so far for the
+// context's request, oldest first.
+func evaluationDiagnostics(ctx context.Context) []FlagDiagnostic {
+	d, ok := ctx.Value(diagnosticsContextKey{}).(*EvaluationDiagnostics)
+	if !ok {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]FlagDiagnostic{}, d.flags...)
+}
+
 // FliptHook implements the Flipt SDK Hook interface for tracking evaluations
 type FliptHook struct {
 	requestCounter metric.Int64Counter
@@ -54,4 +92,15 @@ func (h *FliptHook) After(ctx context.Context, data sdk.AfterHookData) {
 		attribute.String("flipt_reason", data.Reason),
 		attribute.String("flipt_flag_type", data.FlagType),
 	))
+
+	if d, ok := ctx.Value(diagnosticsContextKey{}).(*EvaluationDiagnostics); ok {
+		d.mu.Lock()
+		d.flags = append(d.flags, FlagDiagnostic{
+			FlagKey:  data.FlagKey,
+			FlagType: data.FlagType,
+			Value:    data.Value,
+			Reason:   data.Reason,
+		})
+		d.mu.Unlock()
+	}
 }
@@ -3,8 +3,14 @@ package hotelclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"maps"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,9 +33,22 @@ type Booking struct {
 	TotalPrice         float64 `json:"total_price"`
 	GuestName          string  `json:"guest_name"`
 	GuestEmail         string  `json:"guest_email"`
+	Locale             string  `json:"locale,omitempty"`
 	Checkin            string  `json:"checkin"`
 	Checkout           string  `json:"checkout"`
 	Guests             int     `json:"guests"`
+	CreatedAt          string  `json:"created_at,omitempty"`
+
+	ConfirmationExpiresAt string `json:"confirmation_expires_at,omitempty"`
+}
+
+// Created parses the booking's creation timestamp. The hotel service emits
+// ISO 8601 timestamps without a zone, which are interpreted as UTC.
+func (b *Booking) Created() (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, b.CreatedAt); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05.999999", b.CreatedAt)
 }
 
 // BookingsResponse represents the response from the bookings list endpoint
@@ -40,78 +59,291 @@ type BookingsResponse struct {
 
 // BookingUpdateRequest represents a booking update request
 type BookingUpdateRequest struct {
-	Status             string  `json:"status,omitempty"`
-	ConfirmationNumber *string `json:"confirmation_number,omitempty"`
+	Status                string     `json:"status,omitempty"`
+	ConfirmationNumber    *string    `json:"confirmation_number,omitempty"`
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+}
+
+// ErrNotFound is returned, wrapped, when the requested booking or hotel
+// doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned when the hotel service reports an error in the
+// response body, including responses sent with a 200 status code
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("hotel service error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// RateLimitError is returned when the hotel service responds with 429 Too
+// Many Requests. RetryAfter is zero when the response didn't include a
+// usable Retry-After header.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited by hotel service, retry after %s", e.RetryAfter)
+	}
+	return "rate limited by hotel service"
+}
+
+func newRateLimitError(resp *http.Response) *RateLimitError {
+	header := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return &RateLimitError{RetryAfter: time.Duration(seconds) * time.Second}
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return &RateLimitError{RetryAfter: max(time.Until(at), 0)}
+	}
+	return &RateLimitError{}
 }
 
 // Client is a client for the hotel service
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	healthPath string
+
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	maxRetries   int
+	retryBackoff time.Duration
+	retryBudget  *RetryBudget
+
+	routes       map[string]string
+	maxRedirects int
+
+	scope HotelScope
+}
+
+// HotelScope limits the bookings a Client lists to some hotels, so workers
+// partitioned by hotel don't see each other's bookings. The zero value lists
+// every hotel.
+type HotelScope struct {
+	// HotelIDs, when set, are the only hotels listed
+	HotelIDs []string
+	// ExcludeHotelIDs are never listed
+	ExcludeHotelIDs []string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHealthPath sets the path used by Ping (default "/health")
+func WithHealthPath(path string) Option {
+	return func(c *Client) {
+		c.healthPath = "/" + strings.TrimPrefix(path, "/")
+	}
+}
+
+// WithReadTimeout bounds each read request (bookings, availability, health)
+func WithReadTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.readTimeout = d
+	}
+}
+
+// WithWriteTimeout bounds each booking update
+func WithWriteTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.writeTimeout = d
+	}
 }
 
 // NewClient creates a new hotel service client
-func NewClient(baseURL string, httpClient *http.Client) *Client {
+func NewClient(baseURL string, httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{Timeout: 10 * time.Second}
 	}
-	return &Client{
+	c := &Client{
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		httpClient: httpClient,
+		healthPath: "/health",
+		routes:     maps.Clone(defaultRoutes),
+
+		maxRedirects: defaultMaxRedirects,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	// The HTTP client may be shared, e.g. with the Flipt SDK, so the
+	// redirect policy is set on a copy
+	redirecting := *c.httpClient
+	redirecting.CheckRedirect = c.checkRedirect
+	c.httpClient = &redirecting
+	return c
+}
+
+// Scoped returns a copy of c whose booking lists only include the hotels in
+// scope. Single-booking operations aren't scoped. The copy shares c's HTTP
+// client and retry budget.
+func (c *Client) Scoped(scope HotelScope) *Client {
+	scoped := *c
+	scoped.scope = scope
+	return &scoped
+}
+
+// withTimeout bounds ctx by d; a zero timeout leaves ctx unchanged so the
+// caller's deadline and the HTTP client timeout apply.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// Ping checks that the hotel service is reachable and healthy by requesting
+// its health path, returning an error on any non-2xx response
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, c.readTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+c.healthPath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
 }
 
 // GetBookings fetches bookings with optional status filter
 func (c *Client) GetBookings(ctx context.Context, status string) ([]Booking, error) {
-	url := fmt.Sprintf("%s/api/bookings", c.baseURL)
+	result, err := c.getBookings(ctx, status, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bookings, nil
+}
+
+// GetBookingsPage fetches up to limit bookings starting at offset, with
+// optional status filter. Total is the number of matching bookings across
+// all pages.
+func (c *Client) GetBookingsPage(ctx context.Context, status string, offset, limit int) (*BookingsResponse, error) {
+	return c.getBookings(ctx, status, offset, limit)
+}
+
+// AllBookings iterates over every booking with optional status filter,
+// fetching pageSize bookings at a time so callers can stream large result
+// sets. Iteration stops at the first error, which is yielded last, or when
+// the caller stops ranging.
+func (c *Client) AllBookings(ctx context.Context, status string, pageSize int) iter.Seq2[Booking, error] {
+	return func(yield func(Booking, error) bool) {
+		for offset := 0; ; {
+			page, err := c.getBookings(ctx, status, offset, pageSize)
+			if err != nil {
+				yield(Booking{}, err)
+				return
+			}
+			for _, booking := range page.Bookings {
+				if !yield(booking, nil) {
+					return
+				}
+			}
+			offset += len(page.Bookings)
+			if len(page.Bookings) == 0 || offset >= page.Total {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) getBookings(ctx context.Context, status string, offset, limit int) (*BookingsResponse, error) {
+	ctx, cancel := withTimeout(ctx, c.readTimeout)
+	defer cancel()
+
+	query := url.Values{}
 	if status != "" {
-		url = fmt.Sprintf("%s?status=%s", url, status)
+		query.Set("status", status)
+	}
+	if limit > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if len(c.scope.HotelIDs) > 0 {
+		query.Set("hotel_ids", strings.Join(c.scope.HotelIDs, ","))
+	}
+	if len(c.scope.ExcludeHotelIDs) > 0 {
+		query.Set("exclude_hotel_ids", strings.Join(c.scope.ExcludeHotelIDs, ","))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	reqURL := c.path(RouteListBookings)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var result BookingsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
-	return result.Bookings, nil
+	return &result, nil
 }
 
 // GetBooking fetches a specific booking by ID
 func (c *Client) GetBooking(ctx context.Context, bookingID string) (*Booking, error) {
-	url := fmt.Sprintf("%s/api/bookings/%s", c.baseURL, bookingID)
+	ctx, cancel := withTimeout(ctx, c.readTimeout)
+	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.path(RouteGetBooking, "booking_id", bookingID), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp)
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("booking not found")
+		return nil, fmt.Errorf("booking %w", ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -119,8 +351,8 @@ func (c *Client) GetBooking(ctx context.Context, bookingID string) (*Booking, er
 	}
 
 	var booking Booking
-	if err := json.NewDecoder(resp.Body).Decode(&booking); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &booking); err != nil {
+		return nil, err
 	}
 
 	return &booking, nil
@@ -128,14 +360,15 @@ func (c *Client) GetBooking(ctx context.Context, bookingID string) (*Booking, er
 
 // UpdateBooking updates a booking
 func (c *Client) UpdateBooking(ctx context.Context, bookingID string, update BookingUpdateRequest) error {
-	url := fmt.Sprintf("%s/api/bookings/%s", c.baseURL, bookingID)
+	ctx, cancel := withTimeout(ctx, c.writeTimeout)
+	defer cancel()
 
 	body, err := json.Marshal(update)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "PATCH", url, strings.NewReader(string(body)))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.path(RouteUpdateBooking, "booking_id", bookingID), strings.NewReader(string(body)))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -143,38 +376,53 @@ func (c *Client) UpdateBooking(ctx context.Context, bookingID string, update Boo
 
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return newRateLimitError(resp)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	return nil
+	return decodeResponse(resp, nil)
 }
 
 // GetHotelAvailability checks hotel availability for given dates and guests
 func (c *Client) GetHotelAvailability(ctx context.Context, hotelID, checkin, checkout string, guests int) (*HotelInfo, error) {
-	url := fmt.Sprintf("%s/api/hotels/%s/availability?guests=%d&checkin=%s&checkout=%s", c.baseURL, hotelID, guests, checkin, checkout)
+	ctx, cancel := withTimeout(ctx, c.readTimeout)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("guests", strconv.Itoa(guests))
+	query.Set("checkin", checkin)
+	query.Set("checkout", checkout)
+	reqURL := c.path(RouteAvailability, "hotel_id", hotelID) + "?" + query.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp)
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("hotel not found")
+		return nil, fmt.Errorf("hotel %w", ErrNotFound)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -182,9 +430,34 @@ func (c *Client) GetHotelAvailability(ctx context.Context, hotelID, checkin, che
 	}
 
 	var hotel HotelInfo
-	if err := json.NewDecoder(resp.Body).Decode(&hotel); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &hotel); err != nil {
+		return nil, err
 	}
 
 	return &hotel, nil
 }
+
+// decodeResponse decodes a successful response body into v. Some services
+// report failures as a 200 with an {"error": "..."} body, so the body is
+// checked for an error field first to avoid decoding it into a zero value.
+func decodeResponse(resp *http.Response, v any) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	if len(body) > 0 && json.Unmarshal(body, &errBody) == nil && errBody.Error != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: errBody.Error}
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
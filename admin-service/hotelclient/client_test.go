@@ -0,0 +1,174 @@
+package hotelclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestServer serves handler and records each request's URL
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *[]*url.URL) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		urls []*url.URL
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests abandoned by a client timeout can overlap the next one
+		mu.Lock()
+		urls = append(urls, r.URL)
+		mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &urls
+}
+
+func emptyBookings(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(BookingsResponse{})
+}
+
+func TestScopedClientFiltersBookingListsByHotel(t *testing.T) {
+	srv, urls := newTestServer(t, emptyBookings)
+	client := NewClient(srv.URL, srv.Client())
+
+	scoped := client.Scoped(HotelScope{HotelIDs: []string{"hotel_1", "hotel_2"}})
+	if _, err := scoped.GetBookings(context.Background(), "pending"); err != nil {
+		t.Fatal(err)
+	}
+	excluding := client.Scoped(HotelScope{ExcludeHotelIDs: []string{"hotel_3"}})
+	if _, err := excluding.GetBookingsPage(context.Background(), "pending", 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetBookings(context.Background(), "pending"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := (*urls)[0].Query().Get("hotel_ids"); got != "hotel_1,hotel_2" {
+		t.Errorf("scoped hotel_ids = %q, want %q", got, "hotel_1,hotel_2")
+	}
+	if got := (*urls)[1].Query().Get("exclude_hotel_ids"); got != "hotel_3" {
+		t.Errorf("scoped exclude_hotel_ids = %q, want %q", got, "hotel_3")
+	}
+	if query := (*urls)[2].Query(); query.Has("hotel_ids") || query.Has("exclude_hotel_ids") {
+		t.Errorf("unscoped client sent a hotel filter: %s", (*urls)[2].RawQuery)
+	}
+}
+
+func TestErrorBodyWithOKStatusIsAnAPIError(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error": "database unavailable"}`))
+	})
+	client := NewClient(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"GetBookings": func() error {
+			_, err := client.GetBookings(ctx, "pending")
+			return err
+		},
+		"GetBooking": func() error {
+			booking, err := client.GetBooking(ctx, "b1")
+			if booking != nil {
+				t.Errorf("GetBooking returned %+v alongside the error", booking)
+			}
+			return err
+		},
+		"UpdateBooking": func() error {
+			return client.UpdateBooking(ctx, "b1", BookingUpdateRequest{Status: "confirmed"})
+		},
+		"GetHotelAvailability": func() error {
+			_, err := client.GetHotelAvailability(ctx, "hotel_1", "2030-01-10", "2030-01-12", 2)
+			return err
+		},
+	}
+	for name, call := range calls {
+		var apiErr *APIError
+		if err := call(); !errors.As(err, &apiErr) {
+			t.Errorf("%s: error = %v, want an APIError", name, err)
+			continue
+		}
+		if apiErr.StatusCode != http.StatusOK || apiErr.Message != "database unavailable" {
+			t.Errorf("%s: APIError = %+v", name, apiErr)
+		}
+	}
+}
+
+func TestBodiesWithoutAnErrorFieldDecode(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"booking_id": "b1", "status": "pending", "error": ""}`))
+	})
+	client := NewClient(srv.URL, srv.Client())
+
+	booking, err := client.GetBooking(context.Background(), "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booking.BookingID != "b1" || booking.Status != "pending" {
+		t.Errorf("booking = %+v", booking)
+	}
+}
+
+func TestPingUsesHealthPath(t *testing.T) {
+	for _, tc := range []struct {
+		status  int
+		healthy bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusNoContent, true},
+		{http.StatusServiceUnavailable, false},
+		{http.StatusNotFound, false},
+	} {
+		srv, urls := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		})
+		client := NewClient(srv.URL, srv.Client(), WithHealthPath("/internal/healthz"))
+
+		err := client.Ping(context.Background())
+		if healthy := err == nil; healthy != tc.healthy {
+			t.Errorf("status %d: Ping error = %v, want healthy=%t", tc.status, err, tc.healthy)
+		}
+		if path := (*urls)[0].Path; path != "/internal/healthz" {
+			t.Errorf("status %d: pinged %s, want /internal/healthz", tc.status, path)
+		}
+	}
+}
+
+func TestReadAndWriteTimeoutsApplyPerOperation(t *testing.T) {
+	// Every response takes longer than the short timeout and well within the
+	// long one
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		json.NewEncoder(w).Encode(Booking{BookingID: "booking_1"})
+	})
+	short, long := 20*time.Millisecond, 5*time.Second
+
+	slowWrites := NewClient(srv.URL, srv.Client(), WithReadTimeout(long), WithWriteTimeout(short))
+	if _, err := slowWrites.GetBooking(context.Background(), "booking_1"); err != nil {
+		t.Errorf("read with a %s read timeout: %v", long, err)
+	}
+	if err := slowWrites.UpdateBooking(context.Background(), "booking_1", BookingUpdateRequest{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("write with a %s write timeout = %v, want deadline exceeded", short, err)
+	}
+
+	slowReads := NewClient(srv.URL, srv.Client(), WithReadTimeout(short), WithWriteTimeout(long))
+	if _, err := slowReads.GetBooking(context.Background(), "booking_1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("read with a %s read timeout = %v, want deadline exceeded", short, err)
+	}
+	if err := slowReads.Ping(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("health check with a %s read timeout = %v, want deadline exceeded", short, err)
+	}
+	if err := slowReads.UpdateBooking(context.Background(), "booking_1", BookingUpdateRequest{}); err != nil {
+		t.Errorf("write with a %s write timeout: %v", long, err)
+	}
+}
@@ -0,0 +1,69 @@
+package hotelclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// defaultMaxRedirects is how many redirects a Client follows unless
+// configured with WithMaxRedirects
+const defaultMaxRedirects = 3
+
+// RedirectError is returned, wrapped, when the hotel service responds with
+// a redirect the client won't follow: redirects are disabled, the limit was
+// reached, or following it would change the request method.
+type RedirectError struct {
+	StatusCode int
+	Method     string
+	Location   string
+	Reason     string
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("hotel service redirected %s to %s (status %d): %s", e.Method, e.Location, e.StatusCode, e.Reason)
+}
+
+// WithMaxRedirects sets how many redirects a request may follow; zero
+// disables redirects so they surface as a RedirectError (default 3)
+func WithMaxRedirects(n int) Option {
+	return func(c *Client) {
+		c.maxRedirects = n
+	}
+}
+
+// checkRedirect is the CheckRedirect policy for the client's requests. It
+// limits redirect depth, refuses redirects that would turn a PATCH into a GET
+// and drop its body (301, 302 and 303), and re-injects trace propagation
+// headers so the redirected request stays in the caller's trace.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	prev := via[len(via)-1]
+	redirectErr := func(reason string) error {
+		return &RedirectError{
+			StatusCode: req.Response.StatusCode,
+			Method:     prev.Method,
+			Location:   req.URL.String(),
+			Reason:     reason,
+		}
+	}
+
+	switch {
+	case c.maxRedirects <= 0:
+		return redirectErr("redirects are disabled")
+	case len(via) > c.maxRedirects:
+		return redirectErr(fmt.Sprintf("stopped after %d redirects", c.maxRedirects))
+	case req.Method != via[0].Method:
+		return redirectErr("following it would change the method to " + req.Method)
+	}
+
+	otel.GetTextMapPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	return nil
+}
+
+func isRedirectError(err error) bool {
+	var redirectErr *RedirectError
+	return errors.As(err, &redirectErr)
+}
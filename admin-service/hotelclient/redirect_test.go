@@ -0,0 +1,95 @@
+package hotelclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRedirectsKeepTraceHeadersAndUpdateBodies(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	var movedTraceparent, movedBody string
+	srv, urls := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/bookings/b1" {
+			http.Redirect(w, r, "/moved/b1", http.StatusTemporaryRedirect)
+			return
+		}
+		movedTraceparent = r.Header.Get("Traceparent")
+		body, _ := io.ReadAll(r.Body)
+		movedBody = string(body)
+		json.NewEncoder(w).Encode(Booking{BookingID: "b1"})
+	})
+	client := NewClient(srv.URL, srv.Client())
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	if _, err := client.GetBooking(ctx, "b1"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(movedTraceparent, "00-"+sc.TraceID().String()+"-") {
+		t.Errorf("redirected request traceparent = %q, want the caller's trace %s", movedTraceparent, sc.TraceID())
+	}
+
+	if err := client.UpdateBooking(ctx, "b1", BookingUpdateRequest{Status: "confirmed"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"status":"confirmed"`; !strings.Contains(movedBody, want) {
+		t.Errorf("redirected update body = %q, want it to carry %s", movedBody, want)
+	}
+	if len(*urls) != 4 {
+		t.Errorf("made %d requests, want each call redirected once", len(*urls))
+	}
+}
+
+func TestRefusedRedirects(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		status       int
+		maxRedirects int
+		loop         bool
+		requests     int
+	}{
+		{name: "redirect turning an update into a GET", status: http.StatusFound, maxRedirects: 3, requests: 1},
+		{name: "disabled redirects", status: http.StatusTemporaryRedirect, maxRedirects: 0, requests: 1},
+		{name: "redirect loop", status: http.StatusTemporaryRedirect, maxRedirects: 2, loop: true, requests: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, urls := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.loop || r.URL.Path == "/api/bookings/b1" {
+					http.Redirect(w, r, "/moved"+r.URL.Path, tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+			client := NewClient(srv.URL, srv.Client(), WithMaxRedirects(tc.maxRedirects))
+
+			err := client.UpdateBooking(context.Background(), "b1", BookingUpdateRequest{Status: "confirmed"})
+			var redirectErr *RedirectError
+			if !errors.As(err, &redirectErr) {
+				t.Fatalf("UpdateBooking = %v, want a RedirectError", err)
+			}
+			if redirectErr.Method != http.MethodPatch || redirectErr.StatusCode != tc.status {
+				t.Errorf("RedirectError = %+v, want the PATCH's %d", redirectErr, tc.status)
+			}
+			if len(*urls) != tc.requests {
+				t.Errorf("made %d requests, want %d", len(*urls), tc.requests)
+			}
+		})
+	}
+}
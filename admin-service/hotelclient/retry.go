@@ -0,0 +1,118 @@
+package hotelclient
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudget caps retries to a fraction of requests with a token bucket
+// shared by every request the client makes. Each request earns ratio tokens
+// and each retry spends one, so during a widespread outage retries stop once
+// the bucket is empty and requests fail fast instead of multiplying load on
+// the hotel service.
+type RetryBudget struct {
+	mu        sync.Mutex
+	ratio     float64
+	maxTokens float64
+	tokens    float64
+
+	exhausted atomic.Int64
+}
+
+// NewRetryBudget creates a budget allowing retries for about ratio of
+// requests (0.1 for 10%), with bursts of up to maxTokens retries.
+func NewRetryBudget(ratio float64, maxTokens int) *RetryBudget {
+	return &RetryBudget{
+		ratio:     ratio,
+		maxTokens: float64(maxTokens),
+		tokens:    float64(maxTokens),
+	}
+}
+
+// Exhausted returns how many retries the budget has refused.
+func (b *RetryBudget) Exhausted() int64 {
+	return b.exhausted.Load()
+}
+
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		b.exhausted.Add(1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// WithRetries retries failed GET requests up to maxRetries times, waiting
+// backoff before the first retry and doubling it after each. Connection
+// errors and 502, 503 and 504 responses are retried; other responses,
+// including 429, and refused redirects are returned as is.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithRetryBudget shares budget across the client's retries. Without a
+// budget retries are unbounded beyond maxRetries.
+func WithRetryBudget(budget *RetryBudget) Option {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !isRedirectError(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// do sends req, retrying GET requests as configured by WithRetries within
+// the retry budget. The request context bounds every attempt and backoff.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.retryBudget != nil {
+		c.retryBudget.deposit()
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if req.Method != http.MethodGet || attempt >= c.maxRetries || !retryable(resp, err) {
+			return resp, err
+		}
+		if c.retryBudget != nil && !c.retryBudget.withdraw() {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,89 @@
+package hotelclient
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Logical hotel service operations whose paths can be configured with
+// WithRoutes.
+const (
+	RouteListBookings  = "listBookings"
+	RouteGetBooking    = "getBooking"
+	RouteUpdateBooking = "updateBooking"
+	RouteAvailability  = "availability"
+)
+
+// defaultRoutes are the path templates of the current hotel service API.
+// Placeholders in braces are replaced with path-escaped values.
+var defaultRoutes = map[string]string{
+	RouteListBookings:  "/api/bookings",
+	RouteGetBooking:    "/api/bookings/{booking_id}",
+	RouteUpdateBooking: "/api/bookings/{booking_id}",
+	RouteAvailability:  "/api/hotels/{hotel_id}/availability",
+}
+
+// routeParams are the placeholders each operation's template must contain
+var routeParams = map[string][]string{
+	RouteListBookings:  nil,
+	RouteGetBooking:    {"booking_id"},
+	RouteUpdateBooking: {"booking_id"},
+	RouteAvailability:  {"hotel_id"},
+}
+
+// ValidateRoutes checks path templates for WithRoutes: every operation must be
+// known, and its template must contain each of the operation's placeholders
+// and no others.
+func ValidateRoutes(routes map[string]string) error {
+	for op, template := range routes {
+		params, ok := routeParams[op]
+		if !ok {
+			return fmt.Errorf("unknown hotel service operation %q", op)
+		}
+		expanded := template
+		for _, param := range params {
+			placeholder := "{" + param + "}"
+			if !strings.Contains(expanded, placeholder) {
+				return fmt.Errorf("route %s %q is missing the %s placeholder", op, template, placeholder)
+			}
+			expanded = strings.ReplaceAll(expanded, placeholder, "")
+		}
+		if strings.ContainsAny(expanded, "{}") {
+			return fmt.Errorf("route %s %q has placeholders other than %s", op, template, placeholderList(params))
+		}
+	}
+	return nil
+}
+
+func placeholderList(params []string) string {
+	if len(params) == 0 {
+		return "none"
+	}
+	return "{" + strings.Join(params, "}, {") + "}"
+}
+
+// WithRoutes overrides the path templates of individual operations, e.g. to
+// target another version of the hotel service API. Templates use {name}
+// placeholders: {booking_id} for booking operations and {hotel_id} for
+// availability. Check templates from configuration with ValidateRoutes
+// first; WithRoutes ignores unknown operations.
+func WithRoutes(routes map[string]string) Option {
+	return func(c *Client) {
+		for op, template := range routes {
+			if _, ok := routeParams[op]; ok {
+				c.routes[op] = "/" + strings.TrimPrefix(template, "/")
+			}
+		}
+	}
+}
+
+// path expands the template for op, escaping each parameter so IDs can't
+// change the path's shape.
+func (c *Client) path(op string, params ...string) string {
+	path := c.routes[op]
+	for i := 0; i+1 < len(params); i += 2 {
+		path = strings.ReplaceAll(path, "{"+params[i]+"}", url.PathEscape(params[i+1]))
+	}
+	return c.baseURL + path
+}
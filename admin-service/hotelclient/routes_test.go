@@ -0,0 +1,115 @@
+package hotelclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultRoutes(t *testing.T) {
+	srv, urls := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			if r.URL.Path == "/api/bookings" {
+				emptyBookings(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(Booking{})
+		}
+	})
+	client := NewClient(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	client.GetBookings(ctx, "pending")
+	client.GetBooking(ctx, "b1")
+	client.UpdateBooking(ctx, "b1", BookingUpdateRequest{Status: "confirmed"})
+	client.GetHotelAvailability(ctx, "hotel_1", "2030-01-10", "2030-01-12", 2)
+
+	want := []string{"/api/bookings", "/api/bookings/b1", "/api/bookings/b1", "/api/hotels/hotel_1/availability"}
+	if len(*urls) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(*urls), len(want))
+	}
+	for i, u := range *urls {
+		if u.Path != want[i] {
+			t.Errorf("request %d path = %s, want %s", i, u.Path, want[i])
+		}
+	}
+}
+
+func TestRoutesExpandTemplates(t *testing.T) {
+	client := NewClient("http://hotels", nil, WithRoutes(map[string]string{
+		RouteGetBooking:   "v2/reservations/{booking_id}/details",
+		RouteAvailability: "/v2/properties/{hotel_id}/rooms",
+	}))
+
+	if got, want := client.path(RouteGetBooking, "booking_id", "b1"), "http://hotels/v2/reservations/b1/details"; got != want {
+		t.Errorf("getBooking path = %s, want %s", got, want)
+	}
+	if got, want := client.path(RouteAvailability, "hotel_id", "hotel_1"), "http://hotels/v2/properties/hotel_1/rooms"; got != want {
+		t.Errorf("availability path = %s, want %s", got, want)
+	}
+	if got, want := client.path(RouteUpdateBooking, "booking_id", "b1"), "http://hotels/api/bookings/b1"; got != want {
+		t.Errorf("updateBooking path = %s, want the default %s", got, want)
+	}
+}
+
+func TestRoutesEscapeIDs(t *testing.T) {
+	client := NewClient("http://hotels", nil)
+
+	for id, want := range map[string]string{
+		"../admin":     "http://hotels/api/bookings/..%2Fadmin",
+		"b1?status=x":  "http://hotels/api/bookings/b1%3Fstatus=x",
+		"b 1#frag":     "http://hotels/api/bookings/b%201%23frag",
+		"{booking_id}": "http://hotels/api/bookings/%7Bbooking_id%7D",
+	} {
+		if got := client.path(RouteGetBooking, "booking_id", id); got != want {
+			t.Errorf("path for %q = %s, want %s", id, got, want)
+		}
+	}
+}
+
+func TestEscapedIDsReachTheServerIntact(t *testing.T) {
+	srv, urls := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Booking{})
+	})
+	client := NewClient(srv.URL, srv.Client())
+
+	if _, err := client.GetBooking(context.Background(), "a/b?c"); err != nil {
+		t.Fatal(err)
+	}
+	u := (*urls)[0]
+	if u.Path != "/api/bookings/a/b?c" || u.EscapedPath() != "/api/bookings/a%2Fb%3Fc" || u.RawQuery != "" {
+		t.Errorf("server saw path %s (escaped %s, query %q)", u.Path, u.EscapedPath(), u.RawQuery)
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	valid := map[string]string{
+		RouteListBookings:  "/v2/reservations",
+		RouteGetBooking:    "/v2/reservations/{booking_id}",
+		RouteUpdateBooking: "/v2/reservations/{booking_id}/status",
+		RouteAvailability:  "/v2/properties/{hotel_id}/availability",
+	}
+	if err := ValidateRoutes(valid); err != nil {
+		t.Errorf("valid routes: %v", err)
+	}
+	if err := ValidateRoutes(nil); err != nil {
+		t.Errorf("no routes: %v", err)
+	}
+
+	for name, routes := range map[string]map[string]string{
+		"unknown operation":     {"cancelBooking": "/api/bookings/{booking_id}/cancel"},
+		"missing placeholder":   {RouteGetBooking: "/api/bookings"},
+		"wrong placeholder":     {RouteAvailability: "/api/hotels/{booking_id}/availability"},
+		"leftover placeholder":  {RouteGetBooking: "/api/{version}/bookings/{booking_id}"},
+		"placeholder on a list": {RouteListBookings: "/api/hotels/{hotel_id}/bookings"},
+		"unclosed brace":        {RouteGetBooking: "/api/bookings/{booking_id}/{"},
+	} {
+		if err := ValidateRoutes(routes); err == nil {
+			t.Errorf("%s: ValidateRoutes accepted %v", name, routes)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetApiHotelsHotelIdAvailability proxies an availability check to the hotel
+// service so admins can see what the worker would see for a booking.
+func (s *AdminService) GetApiHotelsHotelIdAvailability(w http.ResponseWriter, r *http.Request, hotelID string, params api.GetApiHotelsHotelIdAvailabilityParams) {
+	ctx, span := startHandlerSpan(r, "get_hotel_availability")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("hotel_id", hotelID),
+		attribute.String("checkin", params.Checkin),
+		attribute.String("checkout", params.Checkout),
+		attribute.Int("guests", params.Guests),
+	)
+
+	checkin, err := time.Parse(time.DateOnly, params.Checkin)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "Invalid checkin date; expected YYYY-MM-DD")
+		return
+	}
+	checkout, err := time.Parse(time.DateOnly, params.Checkout)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "Invalid checkout date; expected YYYY-MM-DD")
+		return
+	}
+	if !checkout.After(checkin) {
+		respondError(w, r, http.StatusBadRequest, "Checkout must be after checkin")
+		return
+	}
+	if params.Guests < 1 {
+		respondError(w, r, http.StatusBadRequest, "Guests must be at least 1")
+		return
+	}
+
+	hotel, err := s.hotelClient.GetHotelAvailability(ctx, hotelID, params.Checkin, params.Checkout, params.Guests)
+	if err != nil {
+		if errors.Is(err, hotelclient.ErrNotFound) {
+			span.SetAttributes(attribute.Bool("found", false))
+			respondError(w, r, http.StatusNotFound, "Hotel not found")
+			return
+		}
+		log.Printf("Error fetching availability for hotel %s: %v", hotelID, err)
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch availability")
+		return
+	}
+
+	span.SetAttributes(attribute.Int("available_rooms", hotel.AvailableRooms))
+	respondJSON(w, http.StatusOK, HotelAvailabilityResponse{
+		HotelID:        hotelID,
+		Checkin:        params.Checkin,
+		Checkout:       params.Checkout,
+		Guests:         params.Guests,
+		AvailableRooms: hotel.AvailableRooms,
+	})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// Rejection reason keys. Metrics record the key; responses, audit entries
+// and logs carry the localized text.
+const (
+	reasonNoAvailability = "no_availability"
+	reasonManual         = "manual"
+	reasonStale          = "stale"
+	reasonBelowMinGuests = "below_min_guests"
+)
+
+// fallbackLanguage is used when a message has no translation in the
+// requested language.
+const fallbackLanguage = "en"
+
+// defaultMessages is the built-in catalog, keyed by language then reason key.
+var defaultMessages = map[string]map[string]string{
+	"en": {
+		reasonNoAvailability: "No rooms available",
+		reasonBelowMinGuests: "Below the hotel's minimum number of guests",
+	},
+	"de": {
+		reasonNoAvailability: "Keine Zimmer verfügbar",
+		reasonBelowMinGuests: "Unter der Mindestanzahl an Gästen des Hotels",
+	},
+	"es": {
+		reasonNoAvailability: "No hay habitaciones disponibles",
+		reasonBelowMinGuests: "Por debajo del número mínimo de huéspedes del hotel",
+	},
+}
+
+// loadMessageCatalog returns the built-in catalog overlaid with the
+// translations in the JSON file at path, if set.
+func loadMessageCatalog(path string) map[string]map[string]string {
+	catalog := map[string]map[string]string{}
+	for lang, messages := range defaultMessages {
+		catalog[lang] = maps.Clone(messages)
+	}
+	if path == "" {
+		return catalog
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Ignoring message catalog %s: %v", path, err)
+		return catalog
+	}
+	var overrides map[string]map[string]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		log.Printf("Ignoring message catalog %s: %v", path, err)
+		return catalog
+	}
+	for lang, messages := range overrides {
+		lang = strings.ToLower(lang)
+		if catalog[lang] == nil {
+			catalog[lang] = map[string]string{}
+		}
+		maps.Copy(catalog[lang], messages)
+	}
+	return catalog
+}
+
+type languageContextKey struct{}
+
+// withLanguage returns a context carrying the preferred response language.
+func withLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// preferredLanguage returns the first language tag of an Accept-Language
+// header, ignoring quality values.
+func preferredLanguage(acceptLanguage string) string {
+	tag, _, _ := strings.Cut(acceptLanguage, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	return strings.TrimSpace(tag)
+}
+
+// localize resolves a reason key for a booking's guest: in the booking's
+// locale, then the request's language, each followed by its base language
+// (de for de-CH), then the default and fallback languages. Keys without any
+// translation are returned as is.
+func (s *AdminService) localize(ctx context.Context, booking *hotelclient.Booking, key string) string {
+	for _, l := range languageChain(booking.Locale, requestLanguage(ctx), s.cfg.DefaultLanguage) {
+		if msg, ok := s.cfg.MessageCatalog[l][key]; ok {
+			return msg
+		}
+	}
+	return key
+}
+
+// requestLanguage returns the language set on ctx with withLanguage.
+func requestLanguage(ctx context.Context) string {
+	lang, _ := ctx.Value(languageContextKey{}).(string)
+	return lang
+}
+
+// languageChain returns the languages to try in order: each of langs
+// followed by its base language, then fallbackLanguage. Tags are
+// lowercased, and empty and repeated ones left out.
+func languageChain(langs ...string) []string {
+	var chain []string
+	for _, lang := range append(langs, fallbackLanguage) {
+		lang = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+		base, _, _ := strings.Cut(lang, "-")
+		for _, l := range []string{lang, base} {
+			if l != "" && !slices.Contains(chain, l) {
+				chain = append(chain, l)
+			}
+		}
+	}
+	return chain
+}
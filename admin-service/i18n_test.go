@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+func TestLanguageChain(t *testing.T) {
+	got := languageChain("de_CH", "FR-ca", "", "de")
+	want := []string{"de-ch", "de", "fr-ca", "fr", "en"}
+	if !slices.Equal(got, want) {
+		t.Errorf("languageChain = %v, want %v", got, want)
+	}
+}
+
+func TestLocalizeFallbackChain(t *testing.T) {
+	catalog := map[string]map[string]string{
+		"en":    {reasonNoAvailability: "No rooms available", reasonBelowMinGuests: "Too few guests"},
+		"de":    {reasonNoAvailability: "Keine Zimmer verfügbar"},
+		"de-ch": {reasonNoAvailability: "Kei Zimmer verfüegbar"},
+		"fr":    {reasonNoAvailability: "Aucune chambre disponible"},
+		"es":    {reasonNoAvailability: "No hay habitaciones disponibles"},
+	}
+
+	for _, tc := range []struct {
+		name, locale, request, defaultLang, key, want string
+	}{
+		{"booking locale", "de-CH", "fr", "es", reasonNoAvailability, "Kei Zimmer verfüegbar"},
+		{"booking base language", "de-AT", "fr", "es", reasonNoAvailability, "Keine Zimmer verfügbar"},
+		{"request language without booking locale", "", "fr-BE", "es", reasonNoAvailability, "Aucune chambre disponible"},
+		{"request language for an untranslated locale", "it", "fr", "es", reasonNoAvailability, "Aucune chambre disponible"},
+		{"default language", "it", "", "es", reasonNoAvailability, "No hay habitaciones disponibles"},
+		{"English last", "it", "nl", "pt", reasonNoAvailability, "No rooms available"},
+		{"missing translation falls through", "de", "fr", "es", reasonBelowMinGuests, "Too few guests"},
+		{"untranslated key", "de", "", "en", "unknown_reason", "unknown_reason"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &AdminService{cfg: Config{DefaultLanguage: tc.defaultLang, MessageCatalog: catalog}}
+			ctx := context.Background()
+			if tc.request != "" {
+				ctx = withLanguage(ctx, tc.request)
+			}
+			booking := &hotelclient.Booking{Locale: tc.locale}
+
+			if got := svc.localize(ctx, booking, tc.key); got != tc.want {
+				t.Errorf("localize = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadMessageCatalogOverlaysBuiltInMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte(`{"FR": {"no_availability": "Complet"}, "de": {"no_availability": "Ausgebucht"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := loadMessageCatalog(path)
+	if got := catalog["fr"][reasonNoAvailability]; got != "Complet" {
+		t.Errorf("fr = %q, want the added translation", got)
+	}
+	if got := catalog["de"][reasonNoAvailability]; got != "Ausgebucht" {
+		t.Errorf("de = %q, want the override", got)
+	}
+	if got := catalog["de"][reasonBelowMinGuests]; got != defaultMessages["de"][reasonBelowMinGuests] {
+		t.Errorf("de below_min_guests = %q, want the built-in message", got)
+	}
+	if defaultMessages["de"][reasonNoAvailability] == "Ausgebucht" {
+		t.Error("loading a catalog changed the built-in messages")
+	}
+}
+
+func TestWorkerRejectsInTheBookingsLocale(t *testing.T) {
+	setClock(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	booking := pendingBooking("b1", "hotel_1")
+	booking.Locale = "es-MX"
+	hotel := newFakeHotelService(t, booking)
+	hotel.availableRooms["hotel_1"] = 0
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", true)
+	evaluator.setBoolean("auto-approval-killswitch", false)
+	evaluator.setBoolean("require-manual-review", false)
+	svc := newTestService(t, evaluator, hotel, nil)
+
+	NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+	entries := svc.auditLog.ForBooking("b1")
+	if len(entries) != 1 || entries[0].Reason != "No hay habitaciones disponibles" || entries[0].ReasonCode != reasonNoAvailability {
+		t.Errorf("audit entries = %+v, want a rejection in Spanish", entries)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	jobStatusPending   = "pending"
+	jobStatusSucceeded = "succeeded"
+	jobStatusFailed    = "failed"
+)
+
+var errJobStoreFull = errors.New("too many approvals in progress")
+
+// Job tracks an approval processed in the background
+type Job struct {
+	ID        string                   `json:"job_id"`
+	BookingID string                   `json:"booking_id"`
+	Status    string                   `json:"status"`
+	StatusURL string                   `json:"status_url"`
+	Error     string                   `json:"error,omitempty"`
+	Outcome   DecisionOutcome          `json:"outcome,omitempty"`
+	Result    *BookingDecisionResponse `json:"result,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// JobStore keeps async approval jobs in memory and runs them. It holds at
+// most size jobs, and finished jobs expire ttl after they complete. It is a
+// Component: stopping it waits for running jobs, so an approval accepted
+// with 202 isn't cut off by shutdown.
+type JobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	size int
+	ttl  time.Duration
+
+	running sync.WaitGroup
+}
+
+func NewJobStore(size int, ttl time.Duration) *JobStore {
+	return &JobStore{
+		jobs: map[string]*Job{},
+		size: max(size, 1),
+		ttl:  ttl,
+	}
+}
+
+// Create registers a pending job for a booking. When the store is full of
+// unexpired jobs the oldest finished job is evicted; if every job is still
+// pending, errJobStoreFull is returned.
+func (s *JobStore) Create(bookingID string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	if len(s.jobs) >= s.size && !s.evictOldestFinishedLocked() {
+		return Job{}, errJobStoreFull
+	}
+
+	now := timeNow()
+	id := fmt.Sprintf("job-%016x", rand.Uint64())
+	job := &Job{
+		ID:        id,
+		BookingID: bookingID,
+		Status:    jobStatusPending,
+		StatusURL: "/api/jobs/" + id,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.jobs[id] = job
+	return *job, nil
+}
+
+// Run runs fn for a created job on a goroutine tracked until Stop, and
+// records its outcome. fn gets a context that isn't cancelled with ctx, so the
+// job outlives the request that started it.
+func (s *JobStore) Run(ctx context.Context, id string, fn func(ctx context.Context) (*BookingDecisionResponse, error)) {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		result, err := fn(context.WithoutCancel(ctx))
+		s.Finish(id, result, err)
+	}()
+}
+
+func (s *JobStore) Name() string {
+	return "async approval jobs"
+}
+
+func (s *JobStore) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop waits for running jobs to finish or ctx to be done. The server is
+// shut down first, so no new jobs start meanwhile.
+func (s *JobStore) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Finish records the outcome of a job.
+func (s *JobStore) Finish(id string, result *BookingDecisionResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	job.UpdatedAt = timeNow()
+	if err != nil {
+		job.Status = jobStatusFailed
+		job.Error = err.Error()
+		job.Outcome = decisionOutcome(err)
+		return
+	}
+	job.Status = jobStatusSucceeded
+	job.Result = result
+}
+
+// Get returns a job by ID unless it is unknown or has expired.
+func (s *JobStore) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (s *JobStore) pruneLocked() {
+	cutoff := timeNow().Add(-s.ttl)
+	for id, job := range s.jobs {
+		if job.Status != jobStatusPending && job.UpdatedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *JobStore) evictOldestFinishedLocked() bool {
+	var oldest *Job
+	for _, job := range s.jobs {
+		if job.Status != jobStatusPending && (oldest == nil || job.UpdatedAt.Before(oldest.UpdatedAt)) {
+			oldest = job
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(s.jobs, oldest.ID)
+	return true
+}
+
+func (s *AdminService) GetApiJobsJobId(w http.ResponseWriter, r *http.Request, jobID string) {
+	_, span := startHandlerSpan(r, "get_job")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("job_id", jobID))
+
+	job, ok := s.jobs.Get(jobID)
+	if !ok {
+		span.SetAttributes(attribute.Bool("found", false))
+		respondError(w, r, http.StatusNotFound, "Job not found")
+		return
+	}
+
+	span.SetAttributes(attribute.String("job_status", job.Status))
+	respondJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobStoreStopWaitsForRunningJobs(t *testing.T) {
+	jobs := NewJobStore(10, time.Minute)
+	job, err := jobs.Create("b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	jobs.Run(context.Background(), job.ID, func(ctx context.Context) (*BookingDecisionResponse, error) {
+		<-release
+		return &BookingDecisionResponse{}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := jobs.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop with a running job = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	if err := jobs.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop = %v", err)
+	}
+	if got, _ := jobs.Get(job.ID); got.Status != jobStatusSucceeded {
+		t.Errorf("job status after Stop = %s, want %s", got.Status, jobStatusSucceeded)
+	}
+}
+
+func TestJobOutlivesRequestContext(t *testing.T) {
+	jobs := NewJobStore(10, time.Minute)
+	job, _ := jobs.Create("b1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	jobs.Run(ctx, job.ID, func(ctx context.Context) (*BookingDecisionResponse, error) {
+		return nil, ctx.Err()
+	})
+	jobs.Stop(context.Background())
+
+	if got, _ := jobs.Get(job.ID); got.Status != jobStatusSucceeded {
+		t.Errorf("job with a cancelled request context finished %s (%s)", got.Status, got.Error)
+	}
+}
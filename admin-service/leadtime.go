@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// leadDays returns the number of whole days from now until a YYYY-MM-DD
+// checkin date, comparing calendar dates in UTC: a checkin today is 0 days
+// out and one in the past is negative.
+func leadDays(checkin string, now time.Time) (int, error) {
+	date, err := time.Parse(time.DateOnly, checkin)
+	if err != nil {
+		return 0, err
+	}
+	y, m, d := now.UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(today).Hours() / 24), nil
+}
+
+// withinLeadTimeWindow reports whether a booking's checkin is between
+// AutoApprovalMinLeadDays and AutoApprovalMaxLeadDays out, inclusive. A zero
+// maximum leaves the window open-ended. Bookings with an unparseable checkin
+// are outside any configured window.
+func (s *AdminService) withinLeadTimeWindow(booking *hotelclient.Booking) (days int, ok bool, err error) {
+	days, err = leadDays(booking.Checkin, timeNow())
+	if err != nil {
+		return 0, false, err
+	}
+	if days < s.cfg.AutoApprovalMinLeadDays {
+		return days, false, nil
+	}
+	if s.cfg.AutoApprovalMaxLeadDays > 0 && days > s.cfg.AutoApprovalMaxLeadDays {
+		return days, false, nil
+	}
+	return days, true, nil
+}
+
+// leadTimeWindowEnabled reports whether a lead time window is configured
+func (s *AdminService) leadTimeWindowEnabled() bool {
+	return s.cfg.AutoApprovalMinLeadDays > 0 || s.cfg.AutoApprovalMaxLeadDays > 0
+}
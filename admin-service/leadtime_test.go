@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLeadDaysComparesCalendarDates(t *testing.T) {
+	now := time.Date(2030, time.January, 1, 23, 30, 0, 0, time.UTC)
+	for checkin, want := range map[string]int{
+		"2029-12-31": -1,
+		"2030-01-01": 0,
+		"2030-01-02": 1,
+		"2030-04-01": 90,
+	} {
+		if got, err := leadDays(checkin, now); err != nil || got != want {
+			t.Errorf("leadDays(%s) = %d, %v, want %d", checkin, got, err, want)
+		}
+	}
+	if _, err := leadDays("01/02/2030", now); err == nil {
+		t.Error("leadDays accepted a checkin that isn't YYYY-MM-DD")
+	}
+}
+
+func TestLeadTimeWindowBoundaries(t *testing.T) {
+	setClock(t, time.Date(2030, time.January, 1, 23, 30, 0, 0, time.UTC))
+
+	for _, tc := range []struct {
+		checkin string
+		outcome string
+		days    int64
+		within  bool
+	}{
+		{"2030-01-01", outcomeSkipped, 0, false},
+		{"2030-01-02", outcomeApproved, 1, true},
+		{"2030-04-01", outcomeApproved, 90, true},
+		{"2030-04-02", outcomeSkipped, 91, false},
+		{"someday", outcomeSkipped, 0, false},
+	} {
+		recorder := recordSpans(t)
+		booking := pendingBooking("b1", "hotel_1")
+		booking.Checkin = tc.checkin
+		hotel := newFakeHotelService(t, booking)
+		evaluator := newFakeEvaluator()
+		evaluator.setBoolean("require-manual-review", false)
+		evaluator.setVariant("approval-tier", "standard")
+		svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+			cfg.AutoApprovalMinLeadDays = 1
+			cfg.AutoApprovalMaxLeadDays = 90
+		})
+
+		outcome, err := svc.processBooking(context.Background(), &booking)
+		if err != nil {
+			t.Fatalf("checkin %s: %v", tc.checkin, err)
+		}
+		if outcome != tc.outcome {
+			t.Errorf("checkin %s: outcome = %s, want %s", tc.checkin, outcome, tc.outcome)
+		}
+		if tc.outcome == outcomeSkipped && hotel.booking("b1").Status != "pending" {
+			t.Errorf("checkin %s: booking outside the window was decided", tc.checkin)
+		}
+
+		spans := recorder.Ended()
+		i := slices.IndexFunc(spans, func(span sdktrace.ReadOnlySpan) bool { return span.Name() == "process_booking" })
+		if i < 0 {
+			t.Fatal("no process_booking span recorded")
+		}
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		days, recorded := attrs.Value("lead_days")
+		if wantRecorded := tc.checkin != "someday"; recorded != wantRecorded {
+			t.Errorf("checkin %s: lead_days recorded = %t, want %t", tc.checkin, recorded, wantRecorded)
+		}
+		if recorded && days.AsInt64() != tc.days {
+			t.Errorf("checkin %s: lead_days = %d, want %d", tc.checkin, days.AsInt64(), tc.days)
+		}
+		if within, _ := attrs.Value("within_lead_time_window"); within.AsBool() != tc.within {
+			t.Errorf("checkin %s: within_lead_time_window = %t, want %t", tc.checkin, within.AsBool(), tc.within)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+// Sort fields and directions accepted by the bookings list endpoints
+var (
+	bookingSortFields = []string{"booking_id", "checkin", "price", "status"}
+	sortOrders        = []string{"asc", "desc"}
+)
+
+var invalidSortMessage = "Invalid sort; must be one of: " + strings.Join(bookingSortFields, ", ") +
+	" with order " + strings.Join(sortOrders, " or ")
+
+// bookingListQuery holds the bookings list parameters shared by every API
+// version. Empty fields take their defaults.
+type bookingListQuery struct {
+	status              string
+	includeAvailability bool
+	sort                string
+	order               string
+}
+
+// queryParam returns an optional string-valued query parameter, or "" when
+// it is absent.
+func queryParam[T ~string](p *T) string {
+	if p == nil {
+		return ""
+	}
+	return string(*p)
+}
+
+// validSort reports whether field and order are a known sort. Empty values
+// mean the defaults, booking ID ascending.
+func validSort(field, order string) bool {
+	return (field == "" || slices.Contains(bookingSortFields, field)) &&
+		(order == "" || slices.Contains(sortOrders, order))
+}
+
+// sortBookingList sorts bookings by field in the given order. The booking ID
+// breaks ties, so the result doesn't depend on the order the hotel service
+// returned them in.
+func sortBookingList(bookings []hotelclient.Booking, field, order string) {
+	compare := func(a, b hotelclient.Booking) int {
+		switch field {
+		case "checkin":
+			// Checkin dates are YYYY-MM-DD, so they sort lexically
+			return cmp.Compare(a.Checkin, b.Checkin)
+		case "price":
+			return cmp.Compare(a.TotalPrice, b.TotalPrice)
+		case "status":
+			return cmp.Compare(a.Status, b.Status)
+		}
+		return 0
+	}
+
+	slices.SortFunc(bookings, func(a, b hotelclient.Booking) int {
+		c := cmp.Or(compare(a, b), cmp.Compare(a.BookingID, b.BookingID))
+		if order == "desc" {
+			return -c
+		}
+		return c
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+)
+
+func TestBookingListSortOptions(t *testing.T) {
+	booking := func(id, checkin string, price float64, status string) hotelclient.Booking {
+		b := pendingBooking(id, "hotel_1")
+		b.Checkin, b.TotalPrice, b.Status = checkin, price, status
+		return b
+	}
+	hotel := newFakeHotelService(t,
+		booking("b3", "2030-02-01", 200, "pending"),
+		booking("b1", "2030-02-10", 300, "confirmed"),
+		booking("b2", "2030-02-05", 200, "rejected"),
+		booking("b4", "2030-02-01", 100, "pending"),
+	)
+	svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+
+	for query, want := range map[string][]string{
+		"":                           {"b1", "b2", "b3", "b4"},
+		"?order=desc":                {"b4", "b3", "b2", "b1"},
+		"?sort=booking_id":           {"b1", "b2", "b3", "b4"},
+		"?sort=checkin":              {"b3", "b4", "b2", "b1"},
+		"?sort=checkin&order=desc":   {"b1", "b2", "b4", "b3"},
+		"?sort=price":                {"b4", "b2", "b3", "b1"},
+		"?sort=price&order=desc":     {"b1", "b3", "b2", "b4"},
+		"?sort=status":               {"b1", "b3", "b4", "b2"},
+		"?sort=status&order=desc":    {"b2", "b4", "b3", "b1"},
+		"?sort=price&status=pending": {"b4", "b3"},
+	} {
+		rec := serve(svc, http.MethodGet, "/api/bookings"+query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/bookings%s = %d: %s", query, rec.Code, rec.Body)
+		}
+		var resp BookingListResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, b := range resp.Bookings {
+			got = append(got, b.BookingID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("GET /api/bookings%s = %v, want %v", query, got, want)
+		}
+	}
+
+	for _, query := range []string{"?sort=guest", "?sort=price&order=up", "?sort=Price"} {
+		rec := serve(svc, http.MethodGet, "/api/bookings"+query, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/bookings%s = %d, want 400", query, rec.Code)
+		}
+	}
+}
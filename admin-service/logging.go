@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var levelColors = map[slog.Level]string{
+	slog.LevelDebug: "\033[36m",
+	slog.LevelInfo:  "\033[32m",
+	slog.LevelWarn:  "\033[33m",
+	slog.LevelError: "\033[31m",
+}
+
+// setupLogger installs the default slog logger, writing to stdout or, when
+// path is set, appending to that file. format may be "text" or "json"; when
+// empty, text is used for an interactive terminal and JSON otherwise. The
+// standard log package is routed through the same handler. The returned
+// file is nil when logging to stdout.
+func setupLogger(format, path string) (*LogFile, error) {
+	if path == "" {
+		slog.SetDefault(slog.New(newLogHandler(os.Stdout, format, isTerminal(os.Stdout))))
+		return nil, nil
+	}
+
+	f, err := OpenLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(newLogHandler(f, format, false)))
+	return f, nil
+}
+
+// LogFile is a log file that can be reopened at the same path, so external
+// rotation tools can move it aside and signal the service to start a new one.
+type LogFile struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenLogFile opens path for appending, creating it if needed
+func OpenLogFile(path string) (*LogFile, error) {
+	l := &LogFile{path: path}
+	if err := l.Reopen(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Write(p)
+}
+
+// Reopen closes the current file and opens path again. If the new file
+// can't be opened, logging continues to the old one.
+func (l *LogFile) Reopen() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+
+	l.mu.Lock()
+	old := l.file
+	l.file = f
+	l.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// reopenOnSIGHUP reopens logFile whenever the process receives SIGHUP,
+// until ctx is done. SIGHUP would otherwise terminate the process, so it is
+// caught and ignored when logging to stdout.
+func reopenOnSIGHUP(ctx context.Context, logFile *LogFile) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if logFile == nil {
+				continue
+			}
+			if err := logFile.Reopen(); err != nil {
+				log.Printf("Failed to reopen log file on SIGHUP: %v", err)
+				continue
+			}
+			log.Printf("Reopened log file %s", logFile.path)
+		}
+	}
+}
+
+func newLogHandler(w io.Writer, format string, tty bool) slog.Handler {
+	if format == "" {
+		format = "json"
+		if tty {
+			format = "text"
+		}
+	}
+
+	if format == "json" {
+		return slog.NewJSONHandler(w, nil)
+	}
+
+	opts := &slog.HandlerOptions{}
+	if tty {
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				level := a.Value.Any().(slog.Level)
+				a.Value = slog.StringValue(levelColors[level] + level.String() + "\033[0m")
+			}
+			return a
+		}
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
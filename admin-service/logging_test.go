@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLogHandlerFollowsFormatOverride(t *testing.T) {
+	for _, tc := range []struct {
+		env  string
+		tty  bool
+		json bool
+	}{
+		{"", true, false},
+		{"", false, true},
+		{"json", true, true},
+		{"text", false, false},
+	} {
+		t.Setenv("LOG_FORMAT", tc.env)
+		var buf bytes.Buffer
+		slog.New(newLogHandler(&buf, loadConfig().LogFormat, tc.tty)).Info("hello")
+
+		if isJSON := strings.HasPrefix(buf.String(), "{"); isJSON != tc.json {
+			t.Errorf("LOG_FORMAT=%q on a terminal=%t logged %q, want JSON=%t", tc.env, tc.tty, buf.String(), tc.json)
+		}
+	}
+}
+
+func TestLogHandlerColorsLevelsOnlyOnTerminals(t *testing.T) {
+	for _, tty := range []bool{true, false} {
+		var buf bytes.Buffer
+		slog.New(newLogHandler(&buf, "text", tty)).Warn("careful")
+
+		// The text handler quotes the escape sequence, so only its tail shows
+		if colored := strings.Contains(buf.String(), "[33mWARN"); colored != tty {
+			t.Errorf("terminal=%t logged %q, want colored=%t", tty, buf.String(), tty)
+		}
+	}
+}
+
+func TestLogFileReopensOnSIGHUP(t *testing.T) {
+	// Keep a stray SIGHUP from terminating the test binary before
+	// reopenOnSIGHUP has registered for it
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	logs := captureLog(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "admin.log")
+	logFile, err := OpenLogFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(logFile, "before rotation")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reopenOnSIGHUP(ctx, logFile)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Rotate the file aside, then signal until the service has opened a new
+	// one at the original path
+	rotated := filepath.Join(dir, "admin.log.1")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		syscall.Kill(os.Getpid(), syscall.SIGHUP)
+		time.Sleep(10 * time.Millisecond)
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log file wasn't reopened after SIGHUP")
+		}
+	}
+	cancel()
+	<-done
+
+	fmt.Fprintln(logFile, "after rotation")
+	if !strings.Contains(logs.String(), "Reopened log file") {
+		t.Errorf("reopen wasn't logged: %s", logs)
+	}
+
+	if data, _ := os.ReadFile(rotated); string(data) != "before rotation\n" {
+		t.Errorf("rotated file = %q, want only the line written before rotation", data)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "after rotation\n") {
+		t.Errorf("new file = %q, want the line written after rotation", data)
+	}
+}
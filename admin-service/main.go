@@ -1,14 +1,16 @@
 package main
 
 import (
-	"cmp"
 	"context"
 	_ "embed"
 	"encoding/json"
 	"log"
+	"log/slog"
 	"net/http"
-	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -27,6 +29,13 @@ import (
 //go:embed openapi.json
 var openAPISpec []byte
 
+// version is the build version, set with -ldflags "-X main.version=...".
+var version = "dev"
+
+// hotelRetryBudgetBurst is how many hotel service retries may happen back to
+// back before the retry budget's ratio applies.
+const hotelRetryBudgetBurst = 10
+
 var (
 	tracer trace.Tracer
 	meter  metric.Meter
@@ -38,6 +47,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Truncated")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusNoContent)
@@ -48,106 +58,499 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// HTTP middleware for OpenTelemetry tracing
-func tracingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract trace context from headers
-		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+// HTTP middleware that answers new requests with 503 once shuttingDown is
+// set, so load balancers retry elsewhere instead of waiting on a draining
+// instance. Requests already being served are unaffected, and the connection
+// is closed after the 503 so clients reconnect to another instance.
+func shutdownMiddleware(shuttingDown *atomic.Bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if shuttingDown.Load() {
+				w.Header().Set("Connection", "close")
+				respondError(w, r, http.StatusServiceUnavailable, "Service is shutting down")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-		// Start a new span
-		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path)
-		defer span.End()
+// HTTP middleware for OpenTelemetry tracing. buildAttrs are added to every
+// root span so a trace shows which build served it.
+func tracingMiddleware(excludePaths []string, buildAttrs []attribute.KeyValue) func(http.Handler) http.Handler {
+	responseSize, _ := meter.Int64Histogram(
+		"admin_http_response_size_bytes",
+		metric.WithDescription("Size of HTTP response bodies"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(0, 128, 512, 1024, 4096, 16384, 65536, 262144, 1048576),
+	)
 
-		span.SetAttributes(
-			attribute.String("http.method", r.Method),
-			attribute.String("http.url", r.URL.String()),
-			attribute.String("http.route", r.URL.Path),
-		)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Serve noisy endpoints such as health probes without a span
+			if slices.Contains(excludePaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Extract trace context from headers
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			// Start a new span
+			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path)
+			defer span.End()
+
+			span.SetAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.url", r.URL.String()),
+				attribute.String("http.route", r.URL.Path),
+			)
+			span.SetAttributes(buildAttrs...)
+
+			// Create a custom response writer to capture status code
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			// Serve the request with traced context
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			span.SetAttributes(
+				attribute.Int("http.status_code", rw.statusCode),
+				attribute.Int64("http.response_content_length", rw.BytesWritten()),
+			)
+			// The path is unbounded (booking IDs), so sizes are recorded by
+			// method and status only
+			responseSize.Record(ctx, rw.BytesWritten(), metric.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.Int("http.status_code", rw.statusCode),
+			))
+		})
+	}
+}
 
-		// Create a custom response writer to capture status code
-		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+// streamingPaths serve responses of unbounded size or duration and are exempt from the
+// request deadline; their handlers also clear the server's write timeout.
+// Each upstream call they make is still bounded by the hotel client's
+// timeouts, and a client disconnect stops them.
+var streamingPaths = []string{"/api/bookings/export", "/api/bookings/stream"}
+
+// HTTP middleware that bounds each request with a deadline. The request
+// context is the single budget that every downstream hotel-service and Flipt
+// call in the handler draws from, so sequential calls can't each consume a
+// full timeout.
+func timeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamingPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// Serve the request with traced context
-		next.ServeHTTP(rw, r.WithContext(ctx))
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
 
-		span.SetAttributes(attribute.Int("http.status_code", rw.statusCode))
-	})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
+// HTTP middleware that logs requests taking longer than threshold at warning
+// level, for triaging latency outliers without logging every request.
+// Streaming endpoints are expected to run long and are not logged.
+func slowRequestMiddleware(threshold time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamingPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := timeNow()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			if duration := timeNow().Sub(start); duration > threshold {
+				slog.Warn("slow request",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"duration", duration,
+					"status", rw.statusCode,
+				)
+			}
+		})
+	}
+}
+
+// HTTP middleware that logs one structured line per request at level, with
+// its outcome and the request and trace IDs to correlate it with other logs
+// and traces.
+func accessLogMiddleware(enabled bool, level slog.Level) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := timeNow()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.statusCode),
+				slog.Duration("duration", timeNow().Sub(start)),
+				slog.Int64("bytes", rw.BytesWritten()),
+				slog.String("remote_addr", r.RemoteAddr),
+			}
+			if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+			}
+			slog.LogAttrs(r.Context(), level, "request", attrs...)
+		})
+	}
+}
+
+// responseWriter records the status code and body size of a response.
+// Handlers that write without calling WriteHeader send 200, which is the
+// initial status.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	wroteHeader bool
+	bytes       int64
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
+	// Only the first call takes effect, as with the underlying writer
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// BytesWritten returns the number of body bytes written so far
+func (rw *responseWriter) BytesWritten() int64 {
+	return rw.bytes
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush streamed responses.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func respondJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
 
+// problemErrors makes every error response use application/problem+json,
+// regardless of the request's Accept header.
+var problemErrors bool
+
+// respondError writes an error response. Errors use the simple {"error": ...}
+// format unless problem+json is enabled or the client asks for it. Approve
+// and reject errors also carry their outcome; see respondDecisionError.
+func respondError(w http.ResponseWriter, r *http.Request, status int, message string, outcome ...DecisionOutcome) {
+	var decision DecisionOutcome
+	if len(outcome) > 0 {
+		decision = outcome[0]
+	}
+	if problemErrors || strings.Contains(r.Header.Get("Accept"), "application/problem+json") {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ProblemDetails{
+			Type:     "about:blank",
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   message,
+			Instance: r.URL.Path,
+			Outcome:  decision,
+		})
+		return
+	}
+	respondJSON(w, status, ErrorResponse{Error: message, Outcome: decision})
+}
+
+// respondDecisionError writes an error response for an approve or reject
+// request, carrying the machine-readable outcome alongside the message. The
+// status follows from the outcome: refusals are the client's to resolve,
+// while upstream failures are the server's.
+func respondDecisionError(w http.ResponseWriter, r *http.Request, outcome DecisionOutcome, message string) {
+	status := http.StatusInternalServerError
+	switch outcome {
+	case DecisionAutoApprovalActive:
+		status = http.StatusForbidden
+	case DecisionAlreadyTerminal:
+		status = http.StatusConflict
+	}
+	respondError(w, r, status, message, outcome)
+}
+
+// newFliptClient creates a Flipt client for a namespace in the configured
+// fetch mode, using the instrumented HTTP client.
+func newFliptClient(ctx context.Context, cfg Config, namespace string, httpClient *http.Client) (*sdk.Client, error) {
+	// Create Flipt hook for tracking evaluations
+	fliptHook := NewFliptHook(cfg.FliptEnvironment, namespace)
+
+	// Watch the streaming connection, which the SDK reconnects silently
+	httpClient = &http.Client{
+		Transport: newStreamWatcher(httpClient.Transport, namespace),
+		Timeout:   httpClient.Timeout,
+	}
+
+	opts := []sdk.Option{
+		sdk.WithURL(cfg.FliptURL),
+		sdk.WithNamespace(namespace),
+		sdk.WithEnvironment(cfg.FliptEnvironment),
+		sdk.WithFetchMode(sdk.FetchMode(cfg.FliptFetchMode)),
+		sdk.WithHTTPClient(httpClient),
+		sdk.WithHook(fliptHook),
+		sdk.WithErrorStrategy(sdk.ErrorStrategyFallback),
+	}
+	if cfg.FliptFetchMode == fetchModePolling && cfg.FliptUpdateInterval > 0 {
+		opts = append(opts, sdk.WithUpdateInterval(cfg.FliptUpdateInterval))
+	}
+	return sdk.NewClient(ctx, opts...)
+}
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
-	shutdown := setupOTEL(ctx)
-	defer shutdown()
-
-	tracer = otel.Tracer("admin-service")
-	meter = otel.Meter("admin-service")
 
 	// Get configuration from environment
-	fliptURL := getEnv("FLIPT_URL", "http://flipt:8080")
-	namespace := getEnv("FLIPT_NAMESPACE", "default")
-	environment := getEnv("FLIPT_ENVIRONMENT", "onoffinc")
-	port := getEnv("PORT", "8001")
-	hotelServiceURL := getEnv("HOTEL_SERVICE_URL", "http://hotel-service:8000")
+	cfg := loadConfig()
+	logFile, err := setupLogger(cfg.LogFormat, cfg.LogFile)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	problemErrors = cfg.ProblemJSONErrors
+
+	shutdown := setupOTEL(ctx, cfg)
+	defer shutdown()
+
+	tracer = otel.Tracer(cfg.TracerName, trace.WithInstrumentationVersion(cfg.InstrumentationVersion))
+	meter = otel.Meter(cfg.MeterName, metric.WithInstrumentationVersion(cfg.InstrumentationVersion))
 
 	log.Printf("Starting Admin Service...")
-	log.Printf("Flipt URL: %s", fliptURL)
-	log.Printf("Namespace: %s", namespace)
-	log.Printf("Environment: %s", environment)
-	log.Printf("Hotel Service URL: %s", hotelServiceURL)
+	log.Printf("Flipt URL: %s", cfg.FliptURL)
+	log.Printf("Namespace: %s", cfg.FliptNamespace)
+	log.Printf("Environment: %s", cfg.FliptEnvironment)
+	if cfg.FliptFetchMode == fetchModePolling && cfg.FliptUpdateInterval > 0 {
+		log.Printf("Flipt fetch mode: %s every %s", cfg.FliptFetchMode, cfg.FliptUpdateInterval)
+	} else {
+		log.Printf("Flipt fetch mode: %s", cfg.FliptFetchMode)
+	}
+	log.Printf("Hotel Service URL: %s", cfg.HotelServiceURL)
+	if len(cfg.APIKeys) == 0 {
+		log.Printf("API key authentication disabled")
+	}
+	checkClock()
+
+	// Tune connection reuse; the same pool serves Flipt streaming and hotel
+	// service calls
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.HTTPMaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.HTTPMaxIdleConnsPerHost
+	transport.IdleConnTimeout = cfg.HTTPIdleConnTimeout
 
 	// Create an HTTP client with OpenTelemetry instrumentation
 	httpClient := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
+		Transport: otelhttp.NewTransport(transport),
 		Timeout:   12 * time.Hour,
 	}
 
-	// Create Flipt hook for tracking evaluations
-	fliptHook := NewFliptHook(environment, namespace)
-
-	// Initialize Flipt client with streaming and instrumented HTTP client
-	fliptClient, err := sdk.NewClient(
-		ctx,
-		sdk.WithURL(fliptURL),
-		sdk.WithNamespace(namespace),
-		sdk.WithEnvironment(environment),
-		sdk.WithFetchMode(sdk.FetchModeStreaming),
-		sdk.WithHTTPClient(httpClient),
-		sdk.WithHook(fliptHook),
-		sdk.WithErrorStrategy(sdk.ErrorStrategyFallback),
-	)
+	fliptClient, err := newFliptClient(ctx, cfg, cfg.FliptNamespace, httpClient)
 	if err != nil {
 		log.Fatalf("Failed to create Flipt client: %v", err)
 	}
 	defer fliptClient.Close(ctx)
 
-	log.Println("Flipt client initialized with streaming enabled")
+	log.Printf("Flipt client initialized in %s mode", cfg.FliptFetchMode)
 
-	// Create hotel service client
-	hotelClient := hotelclient.NewClient(hotelServiceURL, httpClient)
+	var (
+		evaluator Evaluator = fliptClient
+		replay    *ReplayEvaluator
+		recorder  *RecordingEvaluator
+	)
+	switch {
+	case cfg.FliptReplayFile != "":
+		if replay, err = NewReplayEvaluator(cfg.FliptReplayFile, cfg.FliptNamespace); err != nil {
+			log.Fatalf("Failed to load Flipt replay file: %v", err)
+		}
+		evaluator = replay
+		log.Printf("Replaying Flipt evaluations from %s", cfg.FliptReplayFile)
+	case cfg.FliptRecordFile != "":
+		if recorder, err = NewRecordingEvaluator(fliptClient, cfg.FliptNamespace, cfg.FliptRecordFile); err != nil {
+			log.Fatalf("Failed to open Flipt recording file: %v", err)
+		}
+		evaluator = recorder
+		log.Printf("Recording Flipt evaluations to %s", cfg.FliptRecordFile)
+	}
+
+	// Chaos mode must be enabled explicitly, never by a failure rate alone
+	var chaos *Chaos
+	if cfg.ChaosEnabled && cfg.ChaosFailureRate > 0 {
+		chaos = NewChaos(cfg.ChaosFailureRate)
+		log.Printf("WARNING: chaos mode enabled, failing %.0f%% of hotel service calls and flag evaluations", cfg.ChaosFailureRate*100)
+	}
+	// Every namespace's evaluator gets the same chaos and deadline wrappers
+	wrapEvaluator := func(evaluator Evaluator) Evaluator {
+		if chaos != nil {
+			evaluator = NewChaosEvaluator(evaluator, chaos)
+		}
+		return NewDeadlineEvaluator(evaluator, cfg.FliptEvaluationTimeout)
+	}
+	evaluator = wrapEvaluator(evaluator)
+
+	// Create hotel service client. Retries share one budget so an outage
+	// can't turn into a retry storm.
+	retryBudget := hotelclient.NewRetryBudget(float64(cfg.HotelRetryBudgetPercent)/100, hotelRetryBudgetBurst)
+	meter.Int64ObservableCounter(
+		"admin_hotel_retry_budget_exhausted_total",
+		metric.WithDescription("Total number of hotel service retries refused because the retry budget was exhausted"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(retryBudget.Exhausted())
+			return nil
+		}),
+	)
+	hotelHTTPClient := httpClient
+	if chaos != nil {
+		hotelHTTPClient = &http.Client{
+			Transport: chaos.Transport(httpClient.Transport),
+			Timeout:   httpClient.Timeout,
+		}
+	}
+	if err := hotelclient.ValidateRoutes(cfg.HotelRoutes); err != nil {
+		log.Fatalf("Invalid HOTEL_ROUTE_* setting: %v", err)
+	}
+	hotelClient := hotelclient.NewClient(cfg.HotelServiceURL, hotelHTTPClient,
+		hotelclient.WithHealthPath(cfg.HotelServiceHealthPath),
+		hotelclient.WithReadTimeout(cfg.HotelReadTimeout),
+		hotelclient.WithWriteTimeout(cfg.HotelWriteTimeout),
+		hotelclient.WithRetries(cfg.HotelMaxRetries, cfg.HotelRetryBackoff),
+		hotelclient.WithRetryBudget(retryBudget),
+		hotelclient.WithRoutes(cfg.HotelRoutes),
+		hotelclient.WithMaxRedirects(cfg.HotelMaxRedirects),
+	)
 
 	// Create admin service
-	adminService := NewAdminService(fliptClient, hotelClient)
+	adminService := NewAdminService(evaluator, hotelClient, cfg, nil)
+
+	// Background components start in registration order and stop in
+	// reverse once the server has shut down
+	supervisor := NewSupervisor(cfg.ComponentStopTimeout)
+	// The recorder stops last, once nothing evaluates flags anymore
+	if recorder != nil {
+		supervisor.Register(recorder)
+	}
+	supervisor.Register(NewLoopComponent("log file reopener", func(ctx context.Context) {
+		reopenOnSIGHUP(ctx, logFile)
+	}))
+
+	// Webhook subscribers are registered before the workers so they stop
+	// after them and deliver their last decisions
+	if len(cfg.WebhookSubscribers) > 0 {
+		webhookClient := &http.Client{Transport: httpClient.Transport, Timeout: cfg.DecisionWebhookTimeout}
+		// The queue must live on storage that outlives the container, which
+		// no default can guarantee
+		if cfg.NotifyPersist && cfg.NotifyQueueDir == "" {
+			log.Fatal("NOTIFY_PERSIST requires NOTIFY_QUEUE_DIR, a directory on a persistent volume")
+		}
+		var subscribers []*WebhookNotifier
+		queuePaths := map[string]string{}
+		for _, subscriber := range cfg.WebhookSubscribers {
+			notifier := NewWebhookNotifier(subscriber, webhookClient, cfg.DecisionWebhookMaxRetries, cfg.DecisionWebhookRetryBackoff)
+			if cfg.NotifyPersist {
+				path := retryQueuePath(cfg.NotifyQueueDir, subscriber.Name)
+				if other, ok := queuePaths[path]; ok {
+					log.Fatalf("Webhook subscribers %q and %q would share the retry queue %s; rename one of them", other, subscriber.Name, path)
+				}
+				queuePaths[path] = subscriber.Name
+
+				queue, err := OpenRetryQueue(path, cfg.NotifyQueueMaxSize)
+				if err != nil {
+					log.Printf("Warning: webhook notifications to %s won't be persisted: %v", subscriber.Name, err)
+				} else {
+					notifier.UseRetryQueue(queue, max(cfg.NotifyRetryInterval, time.Second), cfg.NotifyMaxAge)
+				}
+			}
+			supervisor.Register(notifier)
+			subscribers = append(subscribers, notifier)
+		}
+		adminService.notifier = NewDecisionNotifier(subscribers)
+	}
+
+	// Async approvals may notify subscribers, so they finish before the
+	// notifiers stop
+	supervisor.Register(adminService.jobs)
+
+	// Start an auto-approval worker per tenant namespace. Tenants other than
+	// the service's own get a Flipt client for their namespace. Bookings are
+	// partitioned by hotel, so each is decided by one tenant's worker, and
+	// every worker shares adminService's audit log, events and caches.
+	tenants := cfg.workerTenants()
+	for hotelID, namespace := range cfg.WorkerTenantHotels {
+		if _, ok := tenants[namespace]; !ok {
+			log.Fatalf("WORKER_TENANT_HOTELS assigns hotel %s to %s, which has no worker in WORKER_TENANTS", hotelID, namespace)
+		}
+	}
+	for namespace, interval := range tenants {
+		scope := cfg.tenantHotelScope(namespace)
+		svc := adminService
+		switch {
+		case namespace != cfg.FliptNamespace:
+			if len(scope.HotelIDs) == 0 {
+				log.Fatalf("Tenant %s has no hotels in WORKER_TENANT_HOTELS; its worker would decide every tenant's bookings", namespace)
+			}
+
+			var tenantEvaluator Evaluator
+			if replay != nil {
+				tenantEvaluator = replay.Tenant(namespace)
+			} else {
+				tenantClient, err := newFliptClient(ctx, cfg, namespace, httpClient)
+				if err != nil {
+					log.Fatalf("Failed to create Flipt client for tenant %s: %v", namespace, err)
+				}
+				defer tenantClient.Close(ctx)
+
+				tenantEvaluator = tenantClient
+				if recorder != nil {
+					tenantEvaluator = recorder.Tenant(tenantClient, namespace)
+				}
+			}
+
+			tenantCfg := cfg
+			tenantCfg.FliptNamespace = namespace
+			svc = adminService.newTenantService(wrapEvaluator(tenantEvaluator), hotelClient.Scoped(scope), tenantCfg)
+		case len(scope.ExcludeHotelIDs) > 0:
+			// The API still serves every hotel; only the worker is scoped
+			svc = adminService.newTenantService(adminService.evaluator, hotelClient.Scoped(scope), cfg)
+		}
 
-	// Create and start auto-approval worker
-	worker := NewAutoApprovalWorker(adminService)
-	go worker.Start(ctx)
+		worker := NewAutoApprovalWorker(svc, namespace, interval)
+		supervisor.Register(NewLoopComponent("auto-approval worker "+namespace, worker.Start))
+	}
+	if err := supervisor.Start(ctx); err != nil {
+		log.Fatalf("Failed to start background components: %v", err)
+	}
 
 	// Setup HTTP router
 	mux := http.NewServeMux()
@@ -199,7 +602,7 @@ func main() {
 
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		respondJSON(w, http.StatusOK, map[string]string{"status": "healthy", "service": "admin-service"})
+		respondJSON(w, http.StatusOK, HealthResponse{Status: "healthy", Service: "admin-service"})
 	})
 
 	// OpenAPI spec endpoint
@@ -210,18 +613,33 @@ func main() {
 	})
 
 	handler := api.HandlerFromMux(adminService, mux)
+	handler = metricsRecorderMiddleware(cfg.FliptNamespace)(handler)
+	handler = headerContextMiddleware(cfg.EvaluationContextHeaders)(handler)
 
 	// Apply middlewares
-	handler = corsMiddleware(tracingMiddleware(handler))
+	handler = authMiddleware(cfg.APIKeys, cfg.WebhookSecret)(handler)
+	handler = timeoutMiddleware(cfg.RequestTimeout)(handler)
+	handler = slowRequestMiddleware(cfg.SlowRequestThreshold)(handler)
+	handler = accessLogMiddleware(cfg.AccessLog, cfg.AccessLogLevel)(handler)
+	var buildAttrs []attribute.KeyValue
+	if cfg.TracingBuildAttributes {
+		buildAttrs = buildAttributes()
+	}
+	handler = corsMiddleware(tracingMiddleware(cfg.TracingExcludePaths, buildAttrs)(handler))
+	var shuttingDown atomic.Bool
+	handler = shutdownMiddleware(&shuttingDown)(handler)
 
 	// Start server
 	srv := &http.Server{
-		Addr:         ":" + port,
+		Addr:         ":" + cfg.Port,
 		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Close booking streams so Shutdown isn't held up by connections that
+	// never go idle
+	srv.RegisterOnShutdown(adminService.events.Close)
 
 	// Graceful shutdown
 	go func() {
@@ -230,22 +648,35 @@ func main() {
 		}
 	}()
 
-	log.Printf("Admin Service started on port %s", port)
+	log.Printf("Admin Service started on port %s", cfg.Port)
 
 	// Wait for interrupt signal
 	<-ctx.Done()
 
 	log.Println("Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// New requests get a 503 from here on. During the drain period the
+	// server still accepts connections, so load balancers see the 503 and
+	// take the instance out of rotation instead of getting connection
+	// refused
+	shuttingDown.Store(true)
+	if cfg.ShutdownDrainPeriod > 0 {
+		log.Printf("Rejecting new requests for %s before stopping the server", cfg.ShutdownDrainPeriod)
+		time.Sleep(cfg.ShutdownDrainPeriod)
+	}
+
+	// The server stops first so no request is still using a component
+	// while it shuts down; both share one shutdown budget
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		// Background components are stopped all the same, so workers and
+		// notifiers still get to finish
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+	if err := supervisor.Stop(ctx); err != nil {
+		log.Printf("Background components did not stop cleanly: %v", err)
 	}
 
 	log.Println("Server exited")
 }
-
-func getEnv(key, defaultValue string) string {
-	return cmp.Or(os.Getenv(key), defaultValue)
-}
@@ -0,0 +1,619 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	sdk "go.flipt.io/flipt-client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMain(m *testing.M) {
+	// main sets these up from the config; tests use the no-op globals
+	tracer = otel.Tracer("admin-service-test")
+	meter = otel.Meter("admin-service-test")
+	os.Exit(m.Run())
+}
+
+// setClock fixes timeNow at now for the rest of the test
+func setClock(t *testing.T, now time.Time) {
+	t.Helper()
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+}
+
+// fakeEvaluator answers evaluations from fixed flag values. Flags it has no
+// value for fail to evaluate, like flags missing from the namespace.
+// Evaluations match unless setReason says otherwise.
+type fakeEvaluator struct {
+	mu       sync.Mutex
+	booleans map[string]bool
+	variants map[string]string
+	attached map[string]string
+	reasons  map[string]string
+	segments map[string][]string
+	errs     map[string]error
+	requests []sdk.EvaluationRequest
+}
+
+func newFakeEvaluator() *fakeEvaluator {
+	return &fakeEvaluator{
+		booleans: map[string]bool{},
+		variants: map[string]string{},
+		attached: map[string]string{},
+		reasons:  map[string]string{},
+		segments: map[string][]string{},
+		errs:     map[string]error{},
+	}
+}
+
+func (e *fakeEvaluator) setBoolean(flagKey string, enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.booleans[flagKey] = enabled
+}
+
+func (e *fakeEvaluator) setVariant(flagKey, variant string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.variants[flagKey] = variant
+}
+
+// setAttachment sets the attachment returned with a variant flag's variant
+func (e *fakeEvaluator) setAttachment(flagKey, attachment string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attached[flagKey] = attachment
+}
+
+// setReason sets the evaluation reason of a flag and the segments a variant
+// flag matched
+func (e *fakeEvaluator) setReason(flagKey, reason string, segmentKeys ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reasons[flagKey] = reason
+	e.segments[flagKey] = segmentKeys
+}
+
+func (e *fakeEvaluator) reason(flagKey string) string {
+	if reason, ok := e.reasons[flagKey]; ok {
+		return reason
+	}
+	return "MATCH_EVALUATION_REASON"
+}
+
+func (e *fakeEvaluator) setError(flagKey string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errs[flagKey] = err
+}
+
+// evaluated returns the requests made for flagKey, oldest first
+func (e *fakeEvaluator) evaluated(flagKey string) []sdk.EvaluationRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var reqs []sdk.EvaluationRequest
+	for _, req := range e.requests {
+		if req.FlagKey == flagKey {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+func (e *fakeEvaluator) lookup(req *sdk.EvaluationRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, *req)
+	return e.errs[req.FlagKey]
+}
+
+func (e *fakeEvaluator) EvaluateBoolean(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.BooleanEvaluationResponse, error) {
+	if err := e.lookup(req); err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	enabled, ok := e.booleans[req.FlagKey]
+	if !ok {
+		return nil, fmt.Errorf("flag %s not found", req.FlagKey)
+	}
+	return &sdk.BooleanEvaluationResponse{FlagKey: req.FlagKey, Enabled: enabled, Reason: e.reason(req.FlagKey)}, nil
+}
+
+func (e *fakeEvaluator) EvaluateVariant(ctx context.Context, req *sdk.EvaluationRequest) (*sdk.VariantEvaluationResponse, error) {
+	if err := e.lookup(req); err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	variant, ok := e.variants[req.FlagKey]
+	if !ok {
+		return nil, fmt.Errorf("flag %s not found", req.FlagKey)
+	}
+	return &sdk.VariantEvaluationResponse{
+		FlagKey:           req.FlagKey,
+		Match:             variant != "",
+		VariantKey:        variant,
+		VariantAttachment: e.attached[req.FlagKey],
+		SegmentKeys:       e.segments[req.FlagKey],
+		Reason:            e.reason(req.FlagKey),
+	}, nil
+}
+
+// fakeHotelService is an in-memory hotel service API. intercept, when set,
+// can answer a request before the fake does by returning true.
+type fakeHotelService struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	bookings       []hotelclient.Booking
+	availableRooms map[string]int
+	requests       []string
+	intercept      func(w http.ResponseWriter, r *http.Request) bool
+}
+
+func newFakeHotelService(t *testing.T, bookings ...hotelclient.Booking) *fakeHotelService {
+	t.Helper()
+	f := &fakeHotelService{bookings: slices.Clone(bookings), availableRooms: map[string]int{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns a hotel client for the fake
+func (f *fakeHotelService) client(opts ...hotelclient.Option) *hotelclient.Client {
+	return hotelclient.NewClient(f.URL, f.Server.Client(), opts...)
+}
+
+// booking returns the fake's current copy of a booking
+func (f *fakeHotelService) booking(id string) hotelclient.Booking {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, booking := range f.bookings {
+		if booking.BookingID == id {
+			return booking
+		}
+	}
+	return hotelclient.Booking{}
+}
+
+// setStatus changes a booking's status as if another client had
+func (f *fakeHotelService) setStatus(id, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := f.index(id); i >= 0 {
+		f.bookings[i].Status = status
+	}
+}
+
+// requested returns the request URIs the fake received with method
+func (f *fakeHotelService) requested(method string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var uris []string
+	for _, req := range f.requests {
+		if uri, ok := strings.CutPrefix(req, method+" "); ok {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+func (f *fakeHotelService) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	intercept := f.intercept
+	f.mu.Unlock()
+	if intercept != nil && intercept(w, r) {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	switch {
+	case r.URL.Path == "/health":
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && path == "bookings":
+		f.listBookings(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "bookings/"):
+		i := f.index(strings.TrimPrefix(path, "bookings/"))
+		if i < 0 {
+			http.Error(w, `{"detail": "Booking not found"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(f.bookings[i])
+	case r.Method == http.MethodPatch && strings.HasPrefix(path, "bookings/"):
+		i := f.index(strings.TrimPrefix(path, "bookings/"))
+		if i < 0 {
+			http.Error(w, `{"detail": "Booking not found"}`, http.StatusNotFound)
+			return
+		}
+		var update hotelclient.BookingUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if update.Status != "" {
+			f.bookings[i].Status = update.Status
+		}
+		if update.ConfirmationNumber != nil {
+			f.bookings[i].ConfirmationNumber = update.ConfirmationNumber
+		}
+		json.NewEncoder(w).Encode(f.bookings[i])
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "hotels/"):
+		hotelID := strings.TrimSuffix(strings.TrimPrefix(path, "hotels/"), "/availability")
+		rooms, ok := f.availableRooms[hotelID]
+		if !ok {
+			rooms = 10
+		}
+		json.NewEncoder(w).Encode(hotelclient.HotelInfo{ID: hotelID, AvailableRooms: rooms})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeHotelService) index(id string) int {
+	return slices.IndexFunc(f.bookings, func(b hotelclient.Booking) bool { return b.BookingID == id })
+}
+
+// listBookings serves the bookings list with the hotel service's status,
+// hotel and paging parameters. Callers hold f.mu.
+func (f *fakeHotelService) listBookings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var matching []hotelclient.Booking
+	for _, booking := range f.bookings {
+		if status := query.Get("status"); status != "" && booking.Status != status {
+			continue
+		}
+		if ids := query.Get("hotel_ids"); ids != "" && !slices.Contains(strings.Split(ids, ","), booking.HotelID) {
+			continue
+		}
+		if ids := query.Get("exclude_hotel_ids"); ids != "" && slices.Contains(strings.Split(ids, ","), booking.HotelID) {
+			continue
+		}
+		matching = append(matching, booking)
+	}
+
+	total := len(matching)
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	matching = matching[min(offset, len(matching)):]
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		matching = matching[:min(limit, len(matching))]
+	}
+	json.NewEncoder(w).Encode(hotelclient.BookingsResponse{Bookings: matching, Total: total})
+}
+
+// newTestService returns a service for evaluator and the fake hotel
+// service, with the default config adjusted by configure.
+func newTestService(t *testing.T, evaluator Evaluator, hotel *fakeHotelService, configure func(*Config)) *AdminService {
+	t.Helper()
+	cfg := loadConfig()
+	if configure != nil {
+		configure(&cfg)
+	}
+	return NewAdminService(evaluator, hotel.client(), cfg, nil)
+}
+
+// serve sends a request to the service's API handler
+func serve(svc *AdminService, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.HandlerFromMux(svc, http.NewServeMux()).ServeHTTP(rec, req)
+	return rec
+}
+
+func pendingBooking(id, hotelID string) hotelclient.Booking {
+	return hotelclient.Booking{
+		BookingID:  id,
+		HotelID:    hotelID,
+		Status:     "pending",
+		TotalPrice: 100,
+		GuestName:  "Guest",
+		GuestEmail: "guest@example.com",
+		Checkin:    "2030-01-10",
+		Checkout:   "2030-01-12",
+		Guests:     2,
+		CreatedAt:  "2030-01-01T00:00:00",
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	for _, tc := range []struct {
+		name, accept string
+		respond      func(w http.ResponseWriter, r *http.Request)
+		status       int
+		outcome      DecisionOutcome
+	}{
+		{"plain error", "", func(w http.ResponseWriter, r *http.Request) {
+			respondError(w, r, http.StatusNotFound, "Booking not found")
+		}, http.StatusNotFound, ""},
+		{"decision error", "", func(w http.ResponseWriter, r *http.Request) {
+			respondDecisionError(w, r, DecisionUpstreamError, "Booking not found")
+		}, http.StatusInternalServerError, DecisionUpstreamError},
+		{"problem decision error", "application/problem+json", func(w http.ResponseWriter, r *http.Request) {
+			respondDecisionError(w, r, DecisionAlreadyTerminal, "Booking not found")
+		}, http.StatusConflict, DecisionAlreadyTerminal},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/bookings/b1/approve", nil)
+			req.Header.Set("Accept", tc.accept)
+			rec := httptest.NewRecorder()
+			tc.respond(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d", rec.Code, tc.status)
+			}
+			var body struct {
+				Error   string          `json:"error"`
+				Detail  string          `json:"detail"`
+				Status  int             `json:"status"`
+				Outcome DecisionOutcome `json:"outcome"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Outcome != tc.outcome {
+				t.Errorf("outcome = %q, want %q", body.Outcome, tc.outcome)
+			}
+			if tc.accept != "" && (body.Status != tc.status || body.Detail != "Booking not found") {
+				t.Errorf("problem = %+v", body)
+			}
+			if tc.accept == "" && body.Error != "Booking not found" {
+				t.Errorf("error = %q", body.Error)
+			}
+		})
+	}
+}
+
+func TestSlowRequestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, now)
+	var delay time.Duration
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stand in for a slow handler by moving the clock forward
+		timeNow = func() time.Time { return now.Add(delay) }
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := slowRequestMiddleware(time.Second)(slow)
+
+	for _, tc := range []struct {
+		path   string
+		delay  time.Duration
+		logged bool
+	}{
+		{"/api/bookings", 500 * time.Millisecond, false},
+		{"/api/bookings/b1", 2 * time.Second, true},
+		{"/api/bookings/export", time.Minute, false},
+	} {
+		logs.Reset()
+		timeNow = func() time.Time { return now }
+		delay = tc.delay
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+		if !tc.logged {
+			if logs.Len() > 0 {
+				t.Errorf("%s after %s logged %s", tc.path, tc.delay, logs.String())
+			}
+			continue
+		}
+		var entry struct {
+			Level    string
+			Msg      string
+			Method   string
+			Path     string
+			Duration time.Duration
+			Status   int
+		}
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("%s after %s: %v", tc.path, tc.delay, err)
+		}
+		if entry.Level != "WARN" || entry.Msg != "slow request" || entry.Method != http.MethodGet ||
+			entry.Path != tc.path || entry.Duration != tc.delay || entry.Status != http.StatusAccepted {
+			t.Errorf("%s after %s logged %+v", tc.path, tc.delay, entry)
+		}
+	}
+}
+
+func TestRequestTimeoutBoundsSequentialHotelCalls(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	// Every hotel-service call takes 150ms, so the approval's three
+	// sequential calls would take 450ms with a timeout each
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		return false
+	}
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	evaluator.setVariant("approval-tier", "standard")
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+		cfg.HotelAvailabilityTimeout = time.Second
+	})
+	handler := timeoutMiddleware(250 * time.Millisecond)(api.HandlerFromMux(svc, http.NewServeMux()))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/bookings/b1/approve?include_availability=true", nil))
+	elapsed := time.Since(start)
+
+	if elapsed > 400*time.Millisecond {
+		t.Errorf("approval took %s, want it bounded by the 250ms request budget", elapsed)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("approve = %d, want the deadline to fail it", rec.Code)
+	}
+	if status := hotel.booking("b1").Status; status != "pending" {
+		t.Errorf("booking status = %s, want pending", status)
+	}
+}
+
+func TestRequestTimeoutSkipsStreamingEndpoints(t *testing.T) {
+	var deadline bool
+	handler := timeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, deadline = r.Context().Deadline()
+	}))
+
+	for _, path := range []string{"/api/bookings/export", "/api/bookings"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		if want := !slices.Contains(streamingPaths, path); deadline != want {
+			t.Errorf("%s: deadline set = %t, want %t", path, deadline, want)
+		}
+	}
+}
+
+func TestResponseWriterCountsBytes(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		write  func(w http.ResponseWriter)
+		status int
+		bytes  int64
+	}{
+		{"implicit header", func(w http.ResponseWriter) {
+			w.Write([]byte("hello, "))
+			w.Write([]byte("world"))
+		}, http.StatusOK, 12},
+		{"explicit header", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"b1"}`))
+		}, http.StatusCreated, 11},
+		{"header after write", func(w http.ResponseWriter) {
+			w.Write([]byte("ok"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusOK, 2},
+		{"no body", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusNoContent)
+		}, http.StatusNoContent, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rw := &responseWriter{ResponseWriter: rec, statusCode: http.StatusOK}
+			tc.write(rw)
+
+			if rw.statusCode != tc.status {
+				t.Errorf("status = %d, want %d", rw.statusCode, tc.status)
+			}
+			if rw.BytesWritten() != tc.bytes || int64(rec.Body.Len()) != tc.bytes {
+				t.Errorf("BytesWritten = %d for a %d byte body, want %d", rw.BytesWritten(), rec.Body.Len(), tc.bytes)
+			}
+		})
+	}
+}
+
+func TestTracingRecordsResponseSize(t *testing.T) {
+	reader := recordMetrics(t)
+	body := strings.Repeat("x", 300)
+	handler := tracingMiddleware(nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/bookings", nil))
+
+	sizes := collectMetric(t, reader, "admin_http_response_size_bytes").Data.(metricdata.Histogram[int64]).DataPoints
+	if len(sizes) != 1 || sizes[0].Count != 1 || sizes[0].Sum != int64(len(body)) {
+		t.Errorf("admin_http_response_size_bytes = %+v, want one %d byte response", sizes, len(body))
+	}
+}
+
+func TestShutdownMiddlewareRejectsNewRequests(t *testing.T) {
+	var shuttingDown atomic.Bool
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := shutdownMiddleware(&shuttingDown)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/slow" {
+			close(started)
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/bookings", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("before shutdown = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// A request in flight when shutdown starts finishes normally
+	inFlight := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(inFlight, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	}()
+	<-started
+	shuttingDown.Store(true)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/bookings", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("during shutdown = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Connection"); got != "close" {
+		t.Errorf("Connection = %q, want close", got)
+	}
+
+	close(release)
+	<-done
+	if inFlight.Code != http.StatusOK {
+		t.Errorf("in-flight request = %d, want %d", inFlight.Code, http.StatusOK)
+	}
+
+	shuttingDown.Store(false)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/bookings", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("after clearing the flag = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestAccessLogDuration(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, now)
+	handler := accessLogMiddleware(true, slog.LevelInfo)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stand in for a slow handler by moving the clock forward
+		timeNow = func() time.Time { return now.Add(1500 * time.Millisecond) }
+		w.WriteHeader(http.StatusCreated)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/bookings/b1/approve", nil))
+
+	var entry struct {
+		Msg      string
+		Path     string
+		Status   int
+		Duration time.Duration
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Msg != "request" || entry.Path != "/api/bookings/b1/approve" || entry.Status != http.StatusCreated || entry.Duration != 1500*time.Millisecond {
+		t.Errorf("access log = %+v, want a 1.5s 201 for the approve path", entry)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "slices"
+
+// otherMetricValue replaces metric attribute values outside a known set
+const otherMetricValue = "other"
+
+// metricReasons are the rejection reasons reported as metric attributes.
+// Anything else is reported as "other" so unexpected or free-form input
+// can't create new series.
+var metricReasons = []string{reasonNoAvailability, reasonManual, reasonStale, reasonBelowMinGuests}
+
+// sanitizeMetricValue returns value if it is one of known, and "other"
+// otherwise. Spans keep the original value.
+func sanitizeMetricValue(value string, known []string) string {
+	if slices.Contains(known, value) {
+		return value
+	}
+	return otherMetricValue
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSanitizeMetricValue(t *testing.T) {
+	for value, want := range map[string]string{
+		reasonNoAvailability:           reasonNoAvailability,
+		reasonManual:                   reasonManual,
+		reasonStale:                    reasonStale,
+		reasonBelowMinGuests:           reasonBelowMinGuests,
+		"":                             otherMetricValue,
+		"No rooms left for 2030-01-10": otherMetricValue,
+		"MANUAL":                       otherMetricValue,
+		"manual\n":                     otherMetricValue,
+	} {
+		if got := sanitizeMetricValue(value, metricReasons); got != want {
+			t.Errorf("sanitizeMetricValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestRejectionReasonsAreSanitizedInMetricsOnly(t *testing.T) {
+	for _, tc := range []struct {
+		reasonKey string
+		metric    string
+	}{
+		{reasonNoAvailability, reasonNoAvailability},
+		{"fraud_suspected", "fraud_suspected"},
+		{"guest asked nicely", otherMetricValue},
+	} {
+		reader := recordMetrics(t)
+		recorder := recordSpans(t)
+		hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+		svc := newTestService(t, newFakeEvaluator(), hotel, func(cfg *Config) {
+			cfg.RejectReasonCodes = []string{"fraud_suspected"}
+		})
+
+		ctx, span := tracer.Start(context.Background(), "reject")
+		booking := hotel.booking("b1")
+		err := svc.rejectBooking(ctx, &booking, tc.reasonKey, "Rejected", false)
+		span.End()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.reasonKey, err)
+		}
+
+		var reasons []string
+		for _, dp := range collectMetric(t, reader, "admin_booking_approvals_total").Data.(metricdata.Sum[int64]).DataPoints {
+			reason, _ := dp.Attributes.Value("reason")
+			reasons = append(reasons, reason.AsString())
+		}
+		if !slices.Equal(reasons, []string{tc.metric}) {
+			t.Errorf("%s: metric reasons = %v, want [%s]", tc.reasonKey, reasons, tc.metric)
+		}
+
+		spans := recorder.Ended()
+		i := slices.IndexFunc(spans, func(span sdktrace.ReadOnlySpan) bool { return span.Name() == "reject" })
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		code, _ := attrs.Value("reason_code")
+		if code.AsString() != tc.reasonKey {
+			t.Errorf("%s: span reason_code = %q, want the original", tc.reasonKey, code.AsString())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MetricsRecorder records metrics for one request or worker sweep with the
+// attributes common to everything it does, currently the tenant and the
+// operation, so every metric from it carries the same labels without each
+// call site rebuilding them. Attributes passed to a call take precedence
+// over the common ones. Methods are safe on a nil recorder, which adds no
+// common attributes.
+//
+// The response status is deliberately not a common attribute: metrics are
+// recorded while the handler runs, before the status is known, and many
+// already carry their own status label for the booking status, which a
+// common one would collide with. The request's root span and
+// admin_http_response_size_bytes carry the response status instead.
+type MetricsRecorder struct {
+	mu    sync.RWMutex
+	attrs []attribute.KeyValue
+}
+
+func NewMetricsRecorder(attrs ...attribute.KeyValue) *MetricsRecorder {
+	return &MetricsRecorder{attrs: attrs}
+}
+
+// Set adds or replaces common attributes
+func (m *MetricsRecorder) Set(attrs ...attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, attr := range attrs {
+		i := slices.IndexFunc(m.attrs, func(kv attribute.KeyValue) bool { return kv.Key == attr.Key })
+		if i < 0 {
+			m.attrs = append(m.attrs, attr)
+		} else {
+			m.attrs[i] = attr
+		}
+	}
+}
+
+// options merges the common attributes with attrs. Later duplicates win
+// when the attribute set is built, so attrs override common ones.
+func (m *MetricsRecorder) options(attrs []attribute.KeyValue) metric.MeasurementOption {
+	if m == nil {
+		return metric.WithAttributes(attrs...)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return metric.WithAttributes(slices.Concat(m.attrs, attrs)...)
+}
+
+func (m *MetricsRecorder) Add(ctx context.Context, counter metric.Int64Counter, n int64, attrs ...attribute.KeyValue) {
+	counter.Add(ctx, n, m.options(attrs))
+}
+
+func (m *MetricsRecorder) Record(ctx context.Context, histogram metric.Int64Histogram, value int64, attrs ...attribute.KeyValue) {
+	histogram.Record(ctx, value, m.options(attrs))
+}
+
+func (m *MetricsRecorder) RecordFloat(ctx context.Context, histogram metric.Float64Histogram, value float64, attrs ...attribute.KeyValue) {
+	histogram.Record(ctx, value, m.options(attrs))
+}
+
+func (m *MetricsRecorder) RecordGauge(ctx context.Context, gauge metric.Int64Gauge, value int64, attrs ...attribute.KeyValue) {
+	gauge.Record(ctx, value, m.options(attrs))
+}
+
+type metricsRecorderContextKey struct{}
+
+// withMetricsRecorder returns a context carrying m
+func withMetricsRecorder(ctx context.Context, m *MetricsRecorder) context.Context {
+	return context.WithValue(ctx, metricsRecorderContextKey{}, m)
+}
+
+// metricsFromContext returns the recorder attached to ctx, or nil, which is
+// still safe to record with
+func metricsFromContext(ctx context.Context) *MetricsRecorder {
+	m, _ := ctx.Value(metricsRecorderContextKey{}).(*MetricsRecorder)
+	return m
+}
+
+// metricsRecorderMiddleware attaches a recorder labelled with the service's
+// tenant to each request. startHandlerSpan adds the operation.
+func metricsRecorderMiddleware(tenant string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m := NewMetricsRecorder(attribute.String("tenant", tenant))
+			next.ServeHTTP(w, r.WithContext(withMetricsRecorder(r.Context(), m)))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// metricsWithoutCommonLabels are recorded outside requests and worker ticks
+var metricsWithoutCommonLabels = map[string]bool{
+	"admin_http_response_size_bytes":           true,
+	"admin_webhook_deliveries_total":           true,
+	"admin_webhook_retry_queue_depth":          true,
+	"admin_chaos_injected_failures_total":      true,
+	"admin_hotel_retry_budget_exhausted_total": true,
+}
+
+// dataPointAttributes returns the attribute sets of a metric's data points
+func dataPointAttributes(m metricdata.Metrics) []attribute.Set {
+	var sets []attribute.Set
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	case metricdata.Gauge[int64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	}
+	return sets
+}
+
+// recordMetrics replaces the package meter with one read by the returned
+// reader for the rest of the test
+func recordMetrics(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	previous := meter
+	meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("admin-service-test")
+	t.Cleanup(func() { meter = previous })
+	return reader
+}
+
+// collectMetric returns the metric called name read from reader
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("%s wasn't recorded", name)
+	return metricdata.Metrics{}
+}
+
+func TestRequestAndWorkerMetricsCarryCommonLabels(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := meter
+	meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("admin-service-test")
+	t.Cleanup(func() { meter = previous })
+
+	full := pendingBooking("b2", "hotel_2")
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"), full, pendingBooking("b3", "hotel_1"))
+	hotel.availableRooms["hotel_2"] = 0
+
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", true)
+	evaluator.setBoolean("auto-approval-killswitch", false)
+	evaluator.setBoolean("require-manual-review", false)
+	evaluator.setVariant("approval-tier", "")
+	evaluator.setVariant("tier-fallback", "gold")
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+		cfg.FliptNamespace = "default"
+		cfg.ApprovalTierFallbackFlags = []string{"tier-fallback"}
+		cfg.DecisionCacheTTL = time.Minute
+		cfg.WorkerFetchPageSize = 2
+	})
+
+	NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+	handler := metricsRecorderMiddleware("default")(api.HandlerFromMux(svc, http.NewServeMux()))
+	for _, target := range []string{"/api/bookings?status=confirmed", "/api/bookings/b1"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d", target, rec.Code)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if metricsWithoutCommonLabels[m.Name] {
+				continue
+			}
+			for _, attrs := range dataPointAttributes(m) {
+				seen[m.Name] = true
+				for _, key := range []attribute.Key{"tenant", "operation"} {
+					if _, ok := attrs.Value(key); !ok {
+						t.Errorf("%s recorded without %s: %v", m.Name, key, attrs.ToSlice())
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"admin_booking_approvals_total",
+		"admin_booking_views_total",
+		"admin_bookings_returned",
+		"admin_approval_tier_fallbacks_total",
+		"admin_unknown_tier_total",
+		"admin_decision_cache_lookups_total",
+		"admin_worker_processed_bookings_total",
+		"admin_worker_remaining_bookings",
+	} {
+		if !seen[name] {
+			t.Errorf("%s wasn't recorded", name)
+		}
+	}
+}
+
+func TestMetricsRecorderCallAttributesOverrideCommonOnes(t *testing.T) {
+	m := NewMetricsRecorder(attribute.String("tenant", "default"), attribute.String("operation", "a"))
+	m.Set(attribute.String("operation", "b"))
+
+	reader := sdkmetric.NewManualReader()
+	counter, _ := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("t").Int64Counter("c")
+	m.Add(context.Background(), counter, 1, attribute.String("tenant", "partners"))
+
+	var rm metricdata.ResourceMetrics
+	reader.Collect(context.Background(), &rm)
+	attrs := dataPointAttributes(rm.ScopeMetrics[0].Metrics[0])[0]
+	if v, _ := attrs.Value("tenant"); v.AsString() != "partners" {
+		t.Errorf("tenant = %s, want partners", v.AsString())
+	}
+	if v, _ := attrs.Value("operation"); v.AsString() != "b" {
+		t.Errorf("operation = %s, want b", v.AsString())
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// notifierQueueSize is how many decisions may wait for delivery before
+// further notifications are dropped
+const notifierQueueSize = 256
+
+// Decision webhook event types
+const (
+	webhookEventApproved  = "booking.approved"
+	webhookEventRejected  = "booking.rejected"
+	webhookEventCancelled = "booking.cancelled"
+)
+
+// decisionStatuses are the booking statuses a subscriber can filter on
+var decisionStatuses = []string{"confirmed", "rejected", "cancelled"}
+
+// WebhookSubscriber is a decision webhook endpoint. Statuses limits it to
+// decisions leaving bookings in those statuses; empty means every decision.
+type WebhookSubscriber struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Statuses []string `json:"statuses,omitempty"`
+	Secret   string   `json:"secret,omitempty"`
+}
+
+// loadWebhookSubscribers returns the subscriber for url, if set, followed by
+// those in the JSON file at path. Entries without a URL are skipped with a
+// warning, and an unreadable file is ignored.
+func loadWebhookSubscribers(path, url, secret string) []WebhookSubscriber {
+	var subscribers []WebhookSubscriber
+	if url != "" {
+		subscribers = append(subscribers, WebhookSubscriber{Name: "default", URL: url, Secret: secret})
+	}
+	if path == "" {
+		return subscribers
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Ignoring webhook subscribers file %s: %v", path, err)
+		return subscribers
+	}
+	var entries []WebhookSubscriber
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("Ignoring webhook subscribers file %s: %v", path, err)
+		return subscribers
+	}
+	for i, sub := range entries {
+		if sub.URL == "" {
+			log.Printf("Warning: ignoring webhook subscriber %d in %s without a url", i+1, path)
+			continue
+		}
+		if sub.Name == "" {
+			sub.Name = fmt.Sprintf("subscriber-%d", i+1)
+		}
+		for _, status := range sub.Statuses {
+			if !slices.Contains(decisionStatuses, status) {
+				log.Printf("Warning: webhook subscriber %s filters on unknown status %q, which never matches", sub.Name, status)
+			}
+		}
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers
+}
+
+// DecisionNotifier fans booking decisions out to every subscriber whose
+// statuses match. Each subscriber has its own queue and retries, so a slow
+// or failing endpoint doesn't hold up the others.
+type DecisionNotifier struct {
+	subscribers []*WebhookNotifier
+}
+
+func NewDecisionNotifier(subscribers []*WebhookNotifier) *DecisionNotifier {
+	return &DecisionNotifier{subscribers: subscribers}
+}
+
+// Notify queues a decision for each matching subscriber without blocking.
+// It is a no-op on a nil notifier, so callers needn't check whether any
+// webhook is configured.
+func (d *DecisionNotifier) Notify(ctx context.Context, event BookingEvent) {
+	if d == nil {
+		return
+	}
+	for _, subscriber := range d.subscribers {
+		if subscriber.wants(event.Status) {
+			subscriber.Notify(ctx, event)
+		}
+	}
+}
+
+// DecisionNotification is the payload POSTed to the decision webhook.
+// Timestamp is also signed, so a receiver can reject replayed deliveries.
+type DecisionNotification struct {
+	Event     string       `json:"event"`
+	Booking   BookingEvent `json:"booking"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// WebhookNotifier delivers booking decisions to one webhook subscriber in the
+// background, in decision order, retrying failed deliveries. It is a
+// Component: deliveries start with Start, and Stop delivers what is queued
+// before returning. With a retry queue, notifications that still can't be
+// delivered are persisted and retried until they expire, also after a
+// restart.
+type WebhookNotifier struct {
+	name       string
+	url        string
+	statuses   []string
+	secret     string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	deliveries metric.Int64Counter
+	queueDepth metric.Int64Gauge
+
+	retries       *RetryQueue
+	retryInterval time.Duration
+	maxAge        time.Duration
+	stopRetries   chan struct{}
+	wg            sync.WaitGroup
+
+	mu     sync.RWMutex
+	queue  chan DecisionNotification
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWebhookNotifier creates a notifier posting to a subscriber's URL.
+// Payloads are signed with its secret when it is set.
+func NewWebhookNotifier(subscriber WebhookSubscriber, httpClient *http.Client, maxRetries int, backoff time.Duration) *WebhookNotifier {
+	deliveries, _ := meter.Int64Counter(
+		"admin_webhook_deliveries_total",
+		metric.WithDescription("Total number of decision webhook notifications, by subscriber, event and result"),
+	)
+
+	queueDepth, _ := meter.Int64Gauge(
+		"admin_webhook_retry_queue_depth",
+		metric.WithDescription("Number of decision webhook notifications persisted for retry, by subscriber"),
+	)
+
+	return &WebhookNotifier{
+		name:        subscriber.Name,
+		url:         subscriber.URL,
+		statuses:    subscriber.Statuses,
+		secret:      subscriber.Secret,
+		httpClient:  httpClient,
+		maxRetries:  maxRetries,
+		backoff:     backoff,
+		deliveries:  deliveries,
+		queueDepth:  queueDepth,
+		queue:       make(chan DecisionNotification, notifierQueueSize),
+		stopRetries: make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// UseRetryQueue persists notifications that fail after every in-memory retry,
+// or that can't be queued, to queue. They are retried every interval, backing
+// off exponentially per notification, until delivered or older than maxAge.
+// It must be called before Start.
+func (n *WebhookNotifier) UseRetryQueue(queue *RetryQueue, interval, maxAge time.Duration) {
+	n.retries = queue
+	n.retryInterval = interval
+	n.maxAge = maxAge
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "decision webhook " + n.name
+}
+
+// wants reports whether the subscriber is notified of decisions leaving a
+// booking in status
+func (n *WebhookNotifier) wants(status string) bool {
+	return len(n.statuses) == 0 || slices.Contains(n.statuses, status)
+}
+
+func (n *WebhookNotifier) Start(ctx context.Context) error {
+	n.ctx, n.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for notification := range n.queue {
+			n.deliver(n.ctx, notification)
+		}
+	}()
+	if n.retries != nil {
+		if depth := n.retries.Len(); depth > 0 {
+			log.Printf("Retrying %d persisted webhook notification(s) to %s", depth, n.name)
+		}
+		n.recordDepth(n.ctx)
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.retryPersisted(n.ctx)
+		}()
+	}
+	go func() {
+		n.wg.Wait()
+		close(n.done)
+	}()
+	return nil
+}
+
+// Stop stops accepting notifications and waits for the queued ones to be
+// delivered. Deliveries still pending when ctx is done are abandoned, or
+// persisted for retry with a retry queue.
+func (n *WebhookNotifier) Stop(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+		close(n.stopRetries)
+	}
+	n.mu.Unlock()
+
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		n.cancel()
+		return ctx.Err()
+	}
+}
+
+// Notify queues a decision for delivery without blocking
+func (n *WebhookNotifier) Notify(ctx context.Context, event BookingEvent) {
+	notification := DecisionNotification{Event: webhookEventRejected, Booking: event}
+	switch event.Status {
+	case "confirmed":
+		notification.Event = webhookEventApproved
+	case "cancelled":
+		notification.Event = webhookEventCancelled
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if !n.closed {
+		select {
+		case n.queue <- notification:
+			return
+		default:
+		}
+	}
+	if n.persist(ctx, notification) {
+		return
+	}
+	log.Printf("Warning: dropping %s webhook to %s for booking %s, delivery queue is full or stopped", notification.Event, n.name, event.BookingID)
+	n.record(ctx, notification.Event, "dropped")
+}
+
+// deliver posts a notification, retrying connection errors, 429 and 5xx
+// responses with exponential backoff. Each attempt is signed with a fresh
+// timestamp.
+func (n *WebhookNotifier) deliver(ctx context.Context, notification DecisionNotification) {
+	backoff := n.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, notification)
+		if err == nil {
+			n.record(ctx, notification.Event, "success")
+			return
+		}
+		if !retry || attempt >= n.maxRetries {
+			log.Printf("Error delivering %s webhook to %s for booking %s after %d attempt(s): %v", notification.Event, n.name, notification.Booking.BookingID, attempt+1, err)
+			if !retry || !n.persist(ctx, notification) {
+				n.record(ctx, notification.Event, "failure")
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			if !n.persist(ctx, notification) {
+				n.record(ctx, notification.Event, "failure")
+			}
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// persist queues a notification for retry, reporting whether it was queued.
+// It is false without a retry queue or when the queue can't be written.
+func (n *WebhookNotifier) persist(ctx context.Context, notification DecisionNotification) bool {
+	if n.retries == nil {
+		return false
+	}
+
+	dropped, err := n.retries.Push(notification, timeNow().Add(n.retryInterval))
+	if err != nil {
+		log.Printf("Error persisting %s webhook to %s for booking %s: %v", notification.Event, n.name, notification.Booking.BookingID, err)
+		return false
+	}
+	n.record(ctx, notification.Event, "queued")
+	if dropped != nil {
+		log.Printf("Warning: webhook retry queue for %s is full, dropping %s webhook for booking %s", n.name, dropped.Event, dropped.Booking.BookingID)
+		n.record(ctx, dropped.Event, "dropped")
+	}
+	n.recordDepth(ctx)
+	return true
+}
+
+// retryPersisted retries the persisted notifications that are due every
+// retry interval until the notifier stops
+func (n *WebhookNotifier) retryPersisted(ctx context.Context) {
+	ticker := time.NewTicker(n.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.stopRetries:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, entry := range n.retries.Due(timeNow()) {
+			select {
+			case <-n.stopRetries:
+				return
+			default:
+			}
+			n.retryOne(ctx, entry)
+		}
+		n.recordDepth(ctx)
+	}
+}
+
+// retryOne makes one delivery attempt for a persisted notification. Failures
+// worth retrying are rescheduled, backing off exponentially, until the
+// notification is older than the maximum age.
+func (n *WebhookNotifier) retryOne(ctx context.Context, entry queuedNotification) {
+	notification := entry.Notification
+	if age := timeNow().Sub(entry.QueuedAt); age > n.maxAge {
+		log.Printf("Warning: expiring %s webhook to %s for booking %s, undelivered after %s and %d retries", notification.Event, n.name, notification.Booking.BookingID, age.Round(time.Second), entry.Attempts)
+		n.record(ctx, notification.Event, "expired")
+		n.removePersisted(entry.ID)
+		return
+	}
+
+	retry, err := n.post(ctx, notification)
+	switch {
+	case err == nil:
+		n.record(ctx, notification.Event, "success")
+		n.removePersisted(entry.ID)
+	case !retry:
+		log.Printf("Error delivering persisted %s webhook to %s for booking %s: %v", notification.Event, n.name, notification.Booking.BookingID, err)
+		n.record(ctx, notification.Event, "failure")
+		n.removePersisted(entry.ID)
+	default:
+		backoff := min(n.retryInterval<<min(entry.Attempts, 16), maxNotifyRetryBackoff)
+		if err := n.retries.Reschedule(entry.ID, timeNow().Add(backoff)); err != nil {
+			log.Printf("Error rescheduling persisted webhook to %s: %v", n.name, err)
+		}
+	}
+}
+
+func (n *WebhookNotifier) removePersisted(id int64) {
+	if err := n.retries.Remove(id); err != nil {
+		log.Printf("Error removing persisted webhook to %s: %v", n.name, err)
+	}
+}
+
+func (n *WebhookNotifier) recordDepth(ctx context.Context) {
+	n.queueDepth.Record(ctx, int64(n.retries.Len()), metric.WithAttributes(
+		attribute.String("subscriber", n.name),
+	))
+}
+
+// post makes one delivery attempt, reporting whether a failure is worth
+// retrying.
+func (n *WebhookNotifier) post(ctx context.Context, notification DecisionNotification) (bool, error) {
+	notification.Timestamp = timeNow().Unix()
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return false, fmt.Errorf("encoding notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		timestamp := strconv.FormatInt(notification.Timestamp, 10)
+		req.Header.Set("X-Signature-Timestamp", timestamp)
+		req.Header.Set("X-Signature", signWebhook(n.secret, timestamp, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+func (n *WebhookNotifier) record(ctx context.Context, event, result string) {
+	n.deliveries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("subscriber", n.name),
+		attribute.String("event", event),
+		attribute.String("result", result),
+	))
+}
+
+// signWebhook returns a "sha256=<hex>" HMAC-SHA256 signature of
+// "<timestamp>.<body>", in the same format inbound webhooks are verified
+// with, covering the timestamp so it can't be altered on replay.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newPersistingNotifier returns a notifier for a subscriber answering with
+// status, persisting undeliverable notifications to a queue in a temporary
+// directory, and the number of deliveries the subscriber received.
+func newPersistingNotifier(t *testing.T, status *atomic.Int32) (*WebhookNotifier, *RetryQueue, *atomic.Int32) {
+	t.Helper()
+	var received atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(srv.Close)
+
+	queue, err := OpenRetryQueue(filepath.Join(t.TempDir(), "subscriber.json"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := NewWebhookNotifier(WebhookSubscriber{Name: "subscriber", URL: srv.URL}, srv.Client(), 0, time.Millisecond)
+	notifier.UseRetryQueue(queue, time.Hour, 24*time.Hour)
+	return notifier, queue, &received
+}
+
+func TestWebhookNotifierPersistsUndeliverableNotifications(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	notifier, queue, received := newPersistingNotifier(t, &status)
+
+	if err := notifier.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	notifier.Notify(context.Background(), BookingEvent{BookingID: "b1", Status: "confirmed"})
+	if err := notifier.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if received.Load() != 1 {
+		t.Errorf("subscriber received %d deliveries, want 1", received.Load())
+	}
+	if queue.Len() != 1 {
+		t.Fatalf("queue holds %d notifications, want 1", queue.Len())
+	}
+	if got := queue.Due(timeNow().Add(time.Hour))[0].Notification; got.Event != webhookEventApproved || got.Booking.BookingID != "b1" {
+		t.Errorf("persisted %+v, want the approval of b1", got)
+	}
+}
+
+func TestWebhookNotifierRetriesPersistedNotifications(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	notifier, queue, received := newPersistingNotifier(t, &status)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	setClock(t, now)
+
+	queue.Push(DecisionNotification{Event: webhookEventRejected, Booking: BookingEvent{BookingID: "b1"}}, now)
+	entry := queue.Due(now)[0]
+
+	// A retryable failure reschedules with backoff
+	notifier.retryOne(context.Background(), entry)
+	if queue.Len() != 1 {
+		t.Fatal("notification removed after a retryable failure")
+	}
+	if due := queue.Due(now.Add(time.Hour - time.Second)); len(due) != 0 {
+		t.Error("notification due again before the retry interval")
+	}
+	entry = queue.Due(now.Add(time.Hour))[0]
+	if entry.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", entry.Attempts)
+	}
+
+	// A successful retry removes it
+	status.Store(http.StatusOK)
+	notifier.retryOne(context.Background(), entry)
+	if queue.Len() != 0 {
+		t.Errorf("queue holds %d notifications after delivery, want 0", queue.Len())
+	}
+	if received.Load() != 2 {
+		t.Errorf("subscriber received %d deliveries, want 2", received.Load())
+	}
+}
+
+func TestWebhookNotifierExpiresOldNotifications(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	notifier, queue, received := newPersistingNotifier(t, &status)
+
+	queuedAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	setClock(t, queuedAt)
+	queue.Push(DecisionNotification{Event: webhookEventRejected, Booking: BookingEvent{BookingID: "b1"}}, queuedAt)
+
+	setClock(t, queuedAt.Add(25*time.Hour))
+	notifier.retryOne(context.Background(), queue.Due(timeNow())[0])
+
+	if queue.Len() != 0 {
+		t.Errorf("expired notification still queued")
+	}
+	if received.Load() != 0 {
+		t.Errorf("expired notification was delivered")
+	}
+}
+
+// recordingSubscriber is a webhook endpoint answering with status and
+// recording the bookings it was notified about and whether each was signed
+type recordingSubscriber struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bookings []string
+	signed   []bool
+}
+
+func newRecordingSubscriber(t *testing.T, status int) *recordingSubscriber {
+	t.Helper()
+	s := &recordingSubscriber{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification DecisionNotification
+		json.NewDecoder(r.Body).Decode(&notification)
+		s.mu.Lock()
+		s.bookings = append(s.bookings, notification.Booking.BookingID)
+		s.signed = append(s.signed, r.Header.Get("X-Signature") != "")
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the notified bookings and whether each was signed
+func (s *recordingSubscriber) received() ([]string, []bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.bookings), slices.Clone(s.signed)
+}
+
+func TestDecisionNotifierFansOutByStatus(t *testing.T) {
+	reader := recordMetrics(t)
+	all := newRecordingSubscriber(t, http.StatusOK)
+	approvals := newRecordingSubscriber(t, http.StatusOK)
+	failing := newRecordingSubscriber(t, http.StatusServiceUnavailable)
+
+	var notifiers []*WebhookNotifier
+	for _, sub := range []WebhookSubscriber{
+		{Name: "all", URL: all.URL, Secret: "shh"},
+		{Name: "approvals", URL: approvals.URL, Statuses: []string{"confirmed"}},
+		{Name: "failing", URL: failing.URL, Statuses: []string{"rejected", "cancelled"}},
+	} {
+		n := NewWebhookNotifier(sub, http.DefaultClient, 1, time.Millisecond)
+		if err := n.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		notifiers = append(notifiers, n)
+	}
+	notifier := NewDecisionNotifier(notifiers)
+
+	notifier.Notify(context.Background(), BookingEvent{BookingID: "b1", Status: "confirmed"})
+	notifier.Notify(context.Background(), BookingEvent{BookingID: "b2", Status: "rejected"})
+	notifier.Notify(context.Background(), BookingEvent{BookingID: "b3", Status: "cancelled"})
+	for _, n := range notifiers {
+		if err := n.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bookings, signed := all.received()
+	if want := []string{"b1", "b2", "b3"}; !slices.Equal(bookings, want) {
+		t.Errorf("unfiltered subscriber received %v, want %v", bookings, want)
+	}
+	if slices.Contains(signed, false) {
+		t.Errorf("subscriber with a secret received unsigned deliveries: %v", signed)
+	}
+	bookings, signed = approvals.received()
+	if want := []string{"b1"}; !slices.Equal(bookings, want) {
+		t.Errorf("approvals subscriber received %v, want %v", bookings, want)
+	}
+	if slices.Contains(signed, true) {
+		t.Error("subscriber without a secret received signed deliveries")
+	}
+	// One retry each for the rejection and the cancellation
+	if bookings, _ = failing.received(); !slices.Equal(bookings, []string{"b2", "b2", "b3", "b3"}) {
+		t.Errorf("failing subscriber received %v, want each booking twice", bookings)
+	}
+
+	results := map[string]int64{}
+	for _, dp := range collectMetric(t, reader, "admin_webhook_deliveries_total").Data.(metricdata.Sum[int64]).DataPoints {
+		subscriber, _ := dp.Attributes.Value("subscriber")
+		result, _ := dp.Attributes.Value("result")
+		results[subscriber.AsString()+" "+result.AsString()] += dp.Value
+	}
+	if want := map[string]int64{"all success": 3, "approvals success": 1, "failing failure": 2}; !maps.Equal(results, want) {
+		t.Errorf("deliveries by subscriber = %v, want %v", results, want)
+	}
+}
+
+func TestLoadWebhookSubscribers(t *testing.T) {
+	logs := captureLog(t)
+	path := filepath.Join(t.TempDir(), "subscribers.json")
+	err := os.WriteFile(path, []byte(`[
+		{"name": "billing", "url": "http://billing/hooks", "statuses": ["confirmed"], "secret": "s1"},
+		{"url": "http://crm/hooks", "statuses": ["approved"]},
+		{"name": "broken"}
+	]`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadWebhookSubscribers(path, "http://default/hooks", "s0")
+	want := []WebhookSubscriber{
+		{Name: "default", URL: "http://default/hooks", Secret: "s0"},
+		{Name: "billing", URL: "http://billing/hooks", Statuses: []string{"confirmed"}, Secret: "s1"},
+		{Name: "subscriber-2", URL: "http://crm/hooks", Statuses: []string{"approved"}},
+	}
+	if !slices.EqualFunc(got, want, func(a, b WebhookSubscriber) bool {
+		return a.Name == b.Name && a.URL == b.URL && a.Secret == b.Secret && slices.Equal(a.Statuses, b.Statuses)
+	}) {
+		t.Errorf("subscribers = %+v, want %+v", got, want)
+	}
+	for _, warning := range []string{`unknown status "approved"`, "subscriber 3", "without a url"} {
+		if !strings.Contains(logs.String(), warning) {
+			t.Errorf("no warning containing %q: %s", warning, logs)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// maxNotifyRetryBackoff caps the wait between retries of a persisted
+// notification
+const maxNotifyRetryBackoff = time.Hour
+
+// retryQueuePath returns the queue file for a subscriber in dir, named after
+// the subscriber with characters unsafe in file names replaced
+func retryQueuePath(dir, subscriber string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, subscriber)
+	return filepath.Join(dir, name+".json")
+}
+
+// queuedNotification is a notification waiting in a RetryQueue
+type queuedNotification struct {
+	ID           int64                `json:"id"`
+	Notification DecisionNotification `json:"notification"`
+	QueuedAt     time.Time            `json:"queued_at"`
+	Attempts     int                  `json:"attempts"`
+	NextAttempt  time.Time            `json:"next_attempt"`
+}
+
+// RetryQueue holds notifications whose delivery failed in a JSON file, so
+// they are retried after a restart. It keeps at most maxSize notifications,
+// dropping the oldest beyond that. Every change rewrites the file.
+type RetryQueue struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int
+	entries []queuedNotification
+	nextID  int64
+}
+
+// OpenRetryQueue loads the queue stored at path, creating its directory. A
+// missing file is an empty queue.
+func OpenRetryQueue(path string, maxSize int) (*RetryQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating notification queue directory: %w", err)
+	}
+
+	q := &RetryQueue{path: path, maxSize: max(maxSize, 1)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading notification queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &q.entries); err != nil {
+		return nil, fmt.Errorf("decoding notification queue %s: %w", path, err)
+	}
+	for _, entry := range q.entries {
+		q.nextID = max(q.nextID, entry.ID)
+	}
+	return q, nil
+}
+
+func (q *RetryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// Push queues a notification for its first retry at next. It returns the
+// notification dropped to make room, if the queue was full.
+func (q *RetryQueue) Push(notification DecisionNotification, next time.Time) (*DecisionNotification, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	q.entries = append(q.entries, queuedNotification{
+		ID:           q.nextID,
+		Notification: notification,
+		QueuedAt:     timeNow(),
+		NextAttempt:  next,
+	})
+
+	var dropped *DecisionNotification
+	if len(q.entries) > q.maxSize {
+		dropped = &q.entries[0].Notification
+		q.entries = q.entries[1:]
+	}
+	return dropped, q.save()
+}
+
+// Due returns the notifications whose next attempt is at or before now,
+// oldest first
+func (q *RetryQueue) Due(now time.Time) []queuedNotification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due []queuedNotification
+	for _, entry := range q.entries {
+		if !entry.NextAttempt.After(now) {
+			due = append(due, entry)
+		}
+	}
+	return due
+}
+
+// Remove drops a notification once it was delivered, failed for good or
+// expired
+func (q *RetryQueue) Remove(id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = slices.DeleteFunc(q.entries, func(entry queuedNotification) bool { return entry.ID == id })
+	return q.save()
+}
+
+// Reschedule records a failed retry of a notification and when to try next
+func (q *RetryQueue) Reschedule(id int64, next time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.entries, func(entry queuedNotification) bool { return entry.ID == id })
+	if i < 0 {
+		return nil
+	}
+	q.entries[i].Attempts++
+	q.entries[i].NextAttempt = next
+	return q.save()
+}
+
+// save writes the queue to a temporary file and renames it over the queue
+// file, so a crash mid-write leaves the previous queue intact. Callers hold
+// q.mu.
+func (q *RetryQueue) save() error {
+	data, err := json.Marshal(q.entries)
+	if err != nil {
+		return fmt.Errorf("encoding notification queue: %w", err)
+	}
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing notification queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("writing notification queue: %w", err)
+	}
+	return nil
+}
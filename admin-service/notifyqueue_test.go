@@ -0,0 +1,104 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func queuedBookingIDs(entries []queuedNotification) []string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.Notification.Booking.BookingID
+	}
+	return ids
+}
+
+func TestRetryQueueSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue", "subscriber.json")
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	setClock(t, now)
+
+	queue, err := OpenRetryQueue(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"b1", "b2", "b3"} {
+		if _, err := queue.Push(DecisionNotification{Event: webhookEventApproved, Booking: BookingEvent{BookingID: id}}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	due := queue.Due(now)
+	if err := queue.Remove(due[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Reschedule(due[1].ID, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenRetryQueue(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Len(); got != 2 {
+		t.Fatalf("reopened queue holds %d notifications, want 2", got)
+	}
+	if got := queuedBookingIDs(reopened.Due(now)); len(got) != 1 || got[0] != "b3" {
+		t.Errorf("due now = %v, want [b3]", got)
+	}
+	later := reopened.Due(now.Add(time.Hour))
+	if got := queuedBookingIDs(later); len(got) != 2 || got[0] != "b2" {
+		t.Errorf("due in an hour = %v, want [b2 b3]", got)
+	}
+	if later[0].Attempts != 1 {
+		t.Errorf("rescheduled notification has %d attempts, want 1", later[0].Attempts)
+	}
+
+	// New notifications don't reuse the IDs of persisted ones
+	if _, err := reopened.Push(DecisionNotification{Booking: BookingEvent{BookingID: "b4"}}, now); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int64]bool{}
+	for _, entry := range reopened.Due(now.Add(time.Hour)) {
+		if seen[entry.ID] {
+			t.Errorf("ID %d used twice", entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+}
+
+func TestRetryQueueDropsOldestWhenFull(t *testing.T) {
+	queue, err := OpenRetryQueue(filepath.Join(t.TempDir(), "subscriber.json"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := timeNow()
+	for _, id := range []string{"b1", "b2"} {
+		if dropped, _ := queue.Push(DecisionNotification{Booking: BookingEvent{BookingID: id}}, now); dropped != nil {
+			t.Fatalf("pushing %s dropped %s", id, dropped.Booking.BookingID)
+		}
+	}
+	dropped, err := queue.Push(DecisionNotification{Booking: BookingEvent{BookingID: "b3"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped == nil || dropped.Booking.BookingID != "b1" {
+		t.Fatalf("dropped %v, want b1", dropped)
+	}
+	if got := queuedBookingIDs(queue.Due(now)); len(got) != 2 || got[0] != "b2" || got[1] != "b3" {
+		t.Errorf("queue = %v, want [b2 b3]", got)
+	}
+}
+
+func TestRetryQueuePath(t *testing.T) {
+	for name, want := range map[string]string{
+		"billing":        "billing.json",
+		"ops-team_2.eu":  "ops-team_2.eu.json",
+		"a/b":            "a_b.json",
+		"../../etc/pass": ".._.._etc_pass.json",
+	} {
+		if got := retryQueuePath("/queues", name); got != filepath.Join("/queues", want) {
+			t.Errorf("retryQueuePath(%q) = %s, want %s", name, got, filepath.Join("/queues", want))
+		}
+	}
+}
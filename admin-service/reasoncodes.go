@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	sdk "go.flipt.io/flipt-client"
+	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReasonCodeCache holds the rejection reason codes last read from Flipt so
+// manual rejections don't evaluate the flag on every request
+type ReasonCodeCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	codes     []string
+	fetchedAt time.Time
+}
+
+func NewReasonCodeCache(ttl time.Duration) *ReasonCodeCache {
+	return &ReasonCodeCache{ttl: ttl}
+}
+
+// Get returns the cached codes, if they are younger than the TTL
+func (c *ReasonCodeCache) Get() ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes == nil || timeNow().Sub(c.fetchedAt) >= c.ttl {
+		return nil, false
+	}
+	return c.codes, true
+}
+
+func (c *ReasonCodeCache) Put(codes []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codes = codes
+	c.fetchedAt = timeNow()
+}
+
+// allowedReasonCodes returns the rejection reason codes manual rejections
+// may use: the JSON list attached to the variant of the reason codes flag,
+// or the static REJECT_REASON_CODES when the flag can't be evaluated or its
+// attachment isn't a list of strings. The fallback is cached too, so an
+// unavailable flag is retried once per TTL rather than on every rejection.
+func (s *AdminService) allowedReasonCodes(ctx context.Context) []string {
+	if codes, ok := s.reasonCodes.Get(); ok {
+		return codes
+	}
+
+	codes, err := s.evaluateReasonCodes(ctx)
+	if err != nil {
+		log.Printf("Warning: using static rejection reason codes: %v", err)
+		codes = s.cfg.RejectReasonCodes
+	}
+	s.reasonCodes.Put(codes)
+	return codes
+}
+
+func (s *AdminService) evaluateReasonCodes(ctx context.Context) ([]string, error) {
+	if s.cfg.RejectReasonCodesFlagKey == "" {
+		return s.cfg.RejectReasonCodes, nil
+	}
+
+	req := &sdk.EvaluationRequest{
+		FlagKey:  s.cfg.RejectReasonCodesFlagKey,
+		EntityID: "admin",
+		Context:  s.evaluationContext(ctx, nil),
+	}
+	result, err := s.evaluator.EvaluateVariant(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %s: %w", req.FlagKey, err)
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("feature_flag.evaluation", trace.WithAttributes(
+		semconv.FeatureFlagKey(req.FlagKey),
+		semconv.FeatureFlagResultVariant(result.VariantKey),
+		semconv.FeatureFlagResultReasonKey.String(result.Reason),
+	))
+
+	var codes []string
+	if err := json.Unmarshal([]byte(result.VariantAttachment), &codes); err != nil {
+		return nil, fmt.Errorf("variant %q of %s has no list of codes attached: %w", result.VariantKey, req.FlagKey, err)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("variant %q of %s allows no codes", result.VariantKey, req.FlagKey)
+	}
+	return codes, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRejectReasonCodesFromFlipt(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		attachment string
+		err        error
+		code       string
+		want       int
+	}{
+		{name: "code from the flag", attachment: `["overbooked", "duplicate"]`, code: "overbooked", want: http.StatusOK},
+		{name: "code missing from the flag", attachment: `["overbooked", "duplicate"]`, code: "suspected_fraud", want: http.StatusBadRequest},
+		{name: "no code", attachment: `["overbooked"]`, want: http.StatusOK},
+		{name: "static code when the flag fails", err: errors.New("connection refused"), code: "suspected_fraud", want: http.StatusOK},
+		{name: "unknown code when the flag fails", err: errors.New("connection refused"), code: "overbooked", want: http.StatusBadRequest},
+		{name: "static code when the attachment isn't a list", attachment: `{"codes": ["overbooked"]}`, code: "suspected_fraud", want: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, hotel := newManualApprovalService(t)
+			evaluator := svc.evaluator.(*fakeEvaluator)
+			evaluator.setVariant("reject-reason-codes", "current")
+			evaluator.setAttachment("reject-reason-codes", tc.attachment)
+			if tc.err != nil {
+				evaluator.setError("reject-reason-codes", tc.err)
+			}
+
+			body := `{"reason": "Rejected"}`
+			if tc.code != "" {
+				body = `{"reason": "Rejected", "reason_code": "` + tc.code + `"}`
+			}
+			rec := serve(svc, http.MethodPost, "/api/bookings/b1/reject", body)
+			if rec.Code != tc.want {
+				t.Fatalf("reject = %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+			if tc.want == http.StatusBadRequest {
+				if !strings.Contains(rec.Body.String(), "expected one of") {
+					t.Errorf("error doesn't list the allowed codes: %s", rec.Body)
+				}
+				if status := hotel.booking("b1").Status; status != "pending" {
+					t.Errorf("booking status = %s after an invalid code, want pending", status)
+				}
+			}
+		})
+	}
+}
+
+func TestRejectReasonCodesAreCached(t *testing.T) {
+	now := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, now)
+	svc, _ := newManualApprovalService(t)
+	svc.reasonCodes = NewReasonCodeCache(30 * time.Second)
+	evaluator := svc.evaluator.(*fakeEvaluator)
+	evaluator.setVariant("reject-reason-codes", "current")
+	evaluator.setAttachment("reject-reason-codes", `["overbooked"]`)
+
+	reject := func() int {
+		return serve(svc, http.MethodPost, "/api/bookings/b1/reject", `{"reason": "Rejected", "reason_code": "overbooked"}`).Code
+	}
+	reject()
+	setClock(t, now.Add(29*time.Second))
+	reject()
+	if n := len(evaluator.evaluated("reject-reason-codes")); n != 1 {
+		t.Errorf("flag evaluated %d times within the TTL, want once", n)
+	}
+
+	// Once the cached list expires, a code removed from the flag is refused
+	evaluator.setAttachment("reject-reason-codes", `["duplicate"]`)
+	setClock(t, now.Add(30*time.Second))
+	if code := reject(); code != http.StatusBadRequest {
+		t.Errorf("reject with a removed code = %d, want 400", code)
+	}
+	if n := len(evaluator.evaluated("reject-reason-codes")); n != 2 {
+		t.Errorf("flag evaluated %d times after the TTL, want twice", n)
+	}
+}
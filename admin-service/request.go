@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type principalContextKey struct{}
+
+// withPrincipal returns a context carrying the authenticated caller.
+func withPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// principalFromContext returns the authenticated caller, if any.
+func principalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// startHandlerSpan starts the span for an API handler and tags it with the
+// request-scoped values every handler reports: request ID, tenant and the
+// authenticated user and role. The returned context also collects the Flipt
+// evaluations made for the request; see evaluationDiagnostics.
+func startHandlerSpan(r *http.Request, name string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(withEvaluationDiagnostics(r.Context()), name)
+	metricsFromContext(ctx).Set(attribute.String("operation", name))
+
+	if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
+		span.SetAttributes(attribute.String("request_id", requestID))
+	}
+	if tenant := r.Header.Get("X-Tenant-ID"); tenant != "" {
+		span.SetAttributes(attribute.String("tenant", tenant))
+	}
+	if principal, ok := principalFromContext(ctx); ok {
+		span.SetAttributes(
+			attribute.String("auth.role", principal.Role.String()),
+			attribute.String("auth.user", principal.User),
+		)
+	}
+	return ctx, span
+}
+
+// maxHeaderContextValueLength bounds header values copied into evaluation
+// contexts, so a client can't send arbitrarily large values to Flipt
+const maxHeaderContextValueLength = 128
+
+type headerContextKey struct{}
+
+// headerContextMiddleware copies the request headers named in mapping into
+// the Flipt evaluation context keys they map to, for every evaluation made
+// while serving the request. Values are sanitized first; headers that are
+// missing or empty once sanitized are left out.
+func headerContextMiddleware(mapping map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(mapping) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys := map[string]string{}
+			for header, key := range mapping {
+				if value := sanitizeContextValue(r.Header.Get(header)); value != "" {
+					keys[key] = value
+				}
+			}
+			if len(keys) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), headerContextKey{}, keys))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// headerContextFromContext returns the evaluation context keys taken from
+// the request's headers, if any
+func headerContextFromContext(ctx context.Context) map[string]string {
+	keys, _ := ctx.Value(headerContextKey{}).(map[string]string)
+	return keys
+}
+
+// sanitizeContextValue trims a header value, drops invalid UTF-8 and
+// non-printable characters and truncates it to maxHeaderContextValueLength
+// bytes.
+func sanitizeContextValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+
+	if len(value) > maxHeaderContextValueLength {
+		// Cut on a rune boundary
+		cut := maxHeaderContextValueLength
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		value = value[:cut]
+	}
+	return strings.TrimSpace(value)
+}
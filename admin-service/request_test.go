@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestHandlerSpanStandardAttributes(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/bookings", nil)
+	r.Header.Set("X-Request-ID", "req-123")
+	r.Header.Set("X-Tenant-ID", "partners")
+	r = r.WithContext(withPrincipal(r.Context(), Principal{User: "alice", Role: RoleApprover}))
+	_, span := startHandlerSpan(r, "GetApiBookings")
+	span.End()
+
+	anonymous := httptest.NewRequest(http.MethodGet, "/api/bookings", nil)
+	_, span = startHandlerSpan(anonymous, "GetApiBookings")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if name := spans[0].Name(); name != "GetApiBookings" {
+		t.Errorf("span name = %q, want GetApiBookings", name)
+	}
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	for key, want := range map[attribute.Key]string{
+		"request_id": "req-123",
+		"tenant":     "partners",
+		"auth.user":  "alice",
+		"auth.role":  "approver",
+	} {
+		if got, ok := attrs.Value(key); !ok || got.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, got.AsString(), want)
+		}
+	}
+
+	if attrs := spans[1].Attributes(); len(attrs) != 0 {
+		t.Errorf("span without request values has attributes %v, want none", attrs)
+	}
+}
+
+func TestHeaderContextMapsIntoEvaluations(t *testing.T) {
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", true)
+	evaluator.setVariant("approval-tier", "standard")
+	svc := newTestService(t, evaluator, newFakeHotelService(t), nil)
+	handler := headerContextMiddleware(map[string]string{
+		"X-Device-Type": "device_type",
+		"X-Region":      "region",
+		"X-Hotel":       "hotel_id",
+	})(api.HandlerFromMux(svc, http.NewServeMux()))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/flags?hotel_id=hotel_1&total_price=100", nil)
+	r.Header.Set("X-Device-Type", " mobile\x00 ")
+	r.Header.Set("X-Hotel", "hotel_9")
+	r.Header.Set("X-Unmapped", "ignored")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/flags = %d: %s", rec.Code, rec.Body)
+	}
+
+	for _, tc := range []struct {
+		flag    string
+		context map[string]string
+	}{
+		// No per-call keys, so the header fills in hotel_id
+		{"auto-approval", map[string]string{"device_type": "mobile", "hotel_id": "hotel_9"}},
+		// The preview booking's own hotel_id wins over the header
+		{"approval-tier", map[string]string{"device_type": "mobile", "hotel_id": "hotel_1"}},
+	} {
+		reqs := evaluator.evaluated(tc.flag)
+		if len(reqs) == 0 {
+			t.Fatalf("%s wasn't evaluated", tc.flag)
+		}
+		for key, want := range tc.context {
+			if got := reqs[0].Context[key]; got != want {
+				t.Errorf("%s context %s = %q, want %q", tc.flag, key, got, want)
+			}
+		}
+		if _, ok := reqs[0].Context["region"]; ok {
+			t.Errorf("%s context has region although the header was missing", tc.flag)
+		}
+		if _, ok := reqs[0].Context["X-Unmapped"]; ok {
+			t.Errorf("%s context has an unmapped header", tc.flag)
+		}
+	}
+}
+
+func TestSanitizeContextValue(t *testing.T) {
+	long := strings.Repeat("a", maxHeaderContextValueLength-1) + "é"
+	for _, tc := range []struct {
+		name, value, want string
+	}{
+		{"plain", "tablet", "tablet"},
+		{"surrounding space", "  tablet\t", "tablet"},
+		{"control characters", "tab\x00le\nt", "tablet"},
+		{"invalid UTF-8", "tab\xfflet", "tablet"},
+		{"only control characters", "\x01\x02", ""},
+		{"truncated", strings.Repeat("a", maxHeaderContextValueLength+10), strings.Repeat("a", maxHeaderContextValueLength)},
+		{"truncated on a rune boundary", long, strings.Repeat("a", maxHeaderContextValueLength-1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sanitizeContextValue(tc.value); got != tc.want {
+				t.Errorf("sanitizeContextValue(%q) = %q, want %q", tc.value, got, tc.want)
+			}
+		})
+	}
+}
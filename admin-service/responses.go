@@ -0,0 +1,174 @@
+package main
+
+import (
+	"time"
+)
+
+// ErrorResponse is returned for all failed requests
+type ErrorResponse struct {
+	Error   string          `json:"error"`
+	Outcome DecisionOutcome `json:"outcome,omitempty"`
+}
+
+// ProblemDetails is an RFC 7807 application/problem+json error
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Outcome DecisionOutcome `json:"outcome,omitempty"`
+}
+
+// HealthResponse is returned by the health check
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+}
+
+// ReadinessResponse is returned by the readiness check
+type ReadinessResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// API response versions. v1 responses are returned bare; later versions are
+// wrapped in an Envelope.
+const (
+	apiVersion1 = "1"
+	apiVersion2 = "2"
+)
+
+// Envelope wraps a response body with the API version that shaped it, so
+// clients of /api/v2 and later can tell response formats apart
+type Envelope[T any] struct {
+	Version string `json:"version"`
+	Data    T      `json:"data"`
+}
+
+// BookingListResponse is returned when listing bookings
+type BookingListResponse struct {
+	Bookings []ListedBooking `json:"bookings"`
+	Total    int             `json:"total"`
+	Status   string          `json:"status"`
+}
+
+// DecisionOutcome is the machine-readable result of an approve or reject
+// request, stable for clients to branch on while Message stays for display
+type DecisionOutcome string
+
+const (
+	DecisionApproved           DecisionOutcome = "APPROVED"
+	DecisionRejected           DecisionOutcome = "REJECTED"
+	DecisionAlreadyTerminal    DecisionOutcome = "ALREADY_TERMINAL"
+	DecisionAutoApprovalActive DecisionOutcome = "AUTO_APPROVAL_ACTIVE"
+	DecisionUpstreamError      DecisionOutcome = "UPSTREAM_ERROR"
+)
+
+// BookingDecisionResponse is returned when a booking is approved or rejected
+type BookingDecisionResponse struct {
+	BookingID             string          `json:"booking_id"`
+	Status                string          `json:"status"`
+	Outcome               DecisionOutcome `json:"outcome"`
+	Message               string          `json:"message"`
+	Reason                string          `json:"reason,omitempty"`
+	Note                  string          `json:"note,omitempty"`
+	AvailableRooms        *int            `json:"available_rooms,omitempty"`
+	ConfirmationExpiresAt *time.Time      `json:"confirmation_expires_at,omitempty"`
+}
+
+// AuditHistoryResponse is returned when reading a booking's audit history
+type AuditHistoryResponse struct {
+	BookingID string       `json:"booking_id"`
+	Entries   []AuditEntry `json:"entries"`
+	Total     int          `json:"total"`
+}
+
+// FlagStatusResponse reports the current state of the admin feature flags
+type FlagStatusResponse struct {
+	AutoApproval AutoApprovalStatus `json:"auto_approval"`
+	ApprovalTier ApprovalTierStatus `json:"approval_tier"`
+	AdminFeature AdminFeatureStatus `json:"admin_feature"`
+	Evaluations  []FlagDiagnostic   `json:"evaluations"`
+}
+
+// AutoApprovalStatus is the evaluated auto-approval flag
+type AutoApprovalStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ApprovalTierStatus is the approval-tier flag, evaluated only for a preview
+// booking
+type ApprovalTierStatus struct {
+	FlagKey   string `json:"flag_key"`
+	Evaluated bool   `json:"evaluated"`
+	Variant   string `json:"variant,omitempty"`
+}
+
+// AdminFeatureStatus is the admin feature flag evaluated for the caller
+type AdminFeatureStatus struct {
+	FlagKey  string `json:"flag_key"`
+	EntityID string `json:"entity_id"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// TierStatsResponse counts approvals per approval tier over a recent window
+type TierStatsResponse struct {
+	Window string         `json:"window"`
+	Since  time.Time      `json:"since"`
+	Tiers  map[string]int `json:"tiers"`
+	Total  int            `json:"total"`
+}
+
+// WebhookResponse is returned after handling an inbound webhook event
+type WebhookResponse struct {
+	BookingID string `json:"booking_id"`
+	Processed bool   `json:"processed"`
+	Message   string `json:"message"`
+}
+
+// BulkItemResult is the outcome of a bulk operation for a single booking
+type BulkItemResult struct {
+	BookingID string `json:"booking_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkSummary aggregates the per-booking results of a bulk operation
+type BulkSummary struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// HotelAvailabilityResponse is returned when checking a hotel's availability
+type HotelAvailabilityResponse struct {
+	HotelID        string `json:"hotel_id"`
+	Checkin        string `json:"checkin"`
+	Checkout       string `json:"checkout"`
+	Guests         int    `json:"guests"`
+	AvailableRooms int    `json:"available_rooms"`
+}
+
+// BookingFlagEvaluation explains how a single flag evaluated for a booking.
+// Enabled is set for boolean flags and Variant for variant flags. MatchedBy
+// says what decided the result: a segment rule (with its SegmentKeys), a
+// boolean rollout, the flag's default or the flag being disabled.
+type BookingFlagEvaluation struct {
+	FlagKey     string   `json:"flag_key"`
+	EntityID    string   `json:"entity_id"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+	Variant     string   `json:"variant,omitempty"`
+	Match       bool     `json:"match"`
+	MatchedBy   string   `json:"matched_by,omitempty"`
+	SegmentKeys []string `json:"segment_keys"`
+	Reason      string   `json:"reason"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// BookingFlagsResponse lists the flag evaluations behind a booking's decision
+type BookingFlagsResponse struct {
+	BookingID string                  `json:"booking_id"`
+	Flags     []BookingFlagEvaluation `json:"flags"`
+}
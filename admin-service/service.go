@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"math/rand/v2"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/flipt-io/labs/admin-service/api"
 	"github.com/flipt-io/labs/admin-service/hotelclient"
@@ -21,47 +25,290 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// terminalBookingMaxAge is how long clients may cache a confirmed or rejected
+// booking.
+const terminalBookingMaxAge = time.Minute
+
+// bookingStatuses are the statuses a booking can have in the hotel service
+var bookingStatuses = []string{"pending", "confirmed", "rejected", "cancelled"}
+
+// Outcomes of the worker processing a pending booking
+const (
+	outcomeApproved = "approved"
+	outcomeRejected = "rejected"
+	outcomeSkipped  = "skipped"
+)
+
+// Audit history pages hold auditDefaultLimit entries unless the caller asks
+// for more, up to auditMaxLimit, so chatty bookings can't produce huge payloads.
+const (
+	auditDefaultLimit = 50
+	auditMaxLimit     = 200
+)
+
+// approvalNoteMaxLength bounds the note an operator can attach to a manual
+// approval, in characters.
+const approvalNoteMaxLength = 500
+
+// maxApprovalBodyBytes bounds the manual approval request body, which only
+// carries the note
+const maxApprovalBodyBytes = 16 << 10
+
 var errAutoApprovalEnabled = errors.New("cannot manually approve/reject when auto-approval is enabled")
 
+// notPendingError is returned when deciding a booking that was already
+// confirmed or rejected.
+type notPendingError struct {
+	status string
+}
+
+func (e *notPendingError) Error() string {
+	return "booking is already " + e.status
+}
+
+// decisionOutcome maps an approve or reject failure to its outcome.
+func decisionOutcome(err error) DecisionOutcome {
+	var notPending *notPendingError
+	switch {
+	case errors.Is(err, errAutoApprovalEnabled):
+		return DecisionAutoApprovalActive
+	case errors.As(err, &notPending):
+		return DecisionAlreadyTerminal
+	default:
+		return DecisionUpstreamError
+	}
+}
+
 type AdminService struct {
-	fliptClient     *sdk.Client
+	evaluator       Evaluator
 	hotelClient     *hotelclient.Client
+	cfg             Config
+	auditLog        *AuditLog
+	hooks           []BookingHook
 	approvalCounter metric.Int64Counter
 	viewCounter     metric.Int64Counter
+
+	bookingsReturnedHistogram metric.Int64Histogram
+
+	availabilityTimeoutCounter metric.Int64Counter
+	autoApprovalSkipCounter    metric.Int64Counter
+	bulkRejectCounter          metric.Int64Counter
+	bulkRejectRunCounter       metric.Int64Counter
+	shadowTierCounter          metric.Int64Counter
+	unknownTierCounter         metric.Int64Counter
+	tierFallbackCounter        metric.Int64Counter
+	timeInPendingHistogram     metric.Float64Histogram
+	decisionCacheCounter       metric.Int64Counter
+	enrichmentTimeoutCounter   metric.Int64Counter
+	writeVerificationCounter   metric.Int64Counter
+	bookingCacheCounter        metric.Int64Counter
+	staleAvailabilityCounter   metric.Int64Counter
+	cancellationCounter        metric.Int64Counter
+
+	jobs        *JobStore
+	decisions   *DecisionCache
+	bookings    *BookingCache
+	events      *EventBus
+	tierStats   *TierStats
+	reasonCodes *ReasonCodeCache
+
+	// notifier is nil unless decision webhook subscribers are configured
+	notifier *DecisionNotifier
 }
 
 var _ api.ServerInterface = (*AdminService)(nil)
 
-func NewAdminService(fliptClient *sdk.Client, hotelClient *hotelclient.Client) *AdminService {
+func NewAdminService(evaluator Evaluator, hotelClient *hotelclient.Client, cfg Config, hooks []BookingHook) *AdminService {
 	viewCounter, _ := meter.Int64Counter(
 		"admin_booking_views_total",
 		metric.WithDescription("Total number of booking views"),
 	)
 
+	// The list size is a distribution, not a label, so it is recorded here
+	// rather than as an attribute on viewCounter
+	bookingsReturnedHistogram, _ := meter.Int64Histogram(
+		"admin_bookings_returned",
+		metric.WithDescription("Number of bookings returned per booking list fetch"),
+		metric.WithUnit("{booking}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 5, 10, 25, 50, 100, 250, 500, 1000),
+	)
+
 	approvalCounter, _ := meter.Int64Counter(
 		"admin_booking_approvals_total",
 		metric.WithDescription("Total number of booking approvals"),
 	)
 
+	availabilityTimeoutCounter, _ := meter.Int64Counter(
+		"admin_availability_timeouts_total",
+		metric.WithDescription("Total number of hotel availability lookups that timed out"),
+	)
+
+	autoApprovalSkipCounter, _ := meter.Int64Counter(
+		"admin_auto_approval_skips_total",
+		metric.WithDescription("Total number of bookings left pending by the auto-approval worker"),
+	)
+
+	bulkRejectCounter, _ := meter.Int64Counter(
+		"admin_bulk_rejections_total",
+		metric.WithDescription("Total number of bookings rejected by bulk operations"),
+	)
+
+	bulkRejectRunCounter, _ := meter.Int64Counter(
+		"admin_bulk_reject_runs_total",
+		metric.WithDescription("Total number of bulk rejection runs by outcome"),
+	)
+
+	shadowTierCounter, _ := meter.Int64Counter(
+		"admin_shadow_tier_evaluations_total",
+		metric.WithDescription("Total number of shadow approval-tier evaluations, labeled with the primary and shadow variants"),
+	)
+
+	tierFallbackCounter, _ := meter.Int64Counter(
+		"admin_approval_tier_fallbacks_total",
+		metric.WithDescription("Total number of approval-tier evaluations resolved by the fallback chain, by the flag that matched"),
+	)
+
+	unknownTierCounter, _ := meter.Int64Counter(
+		"admin_unknown_tier_total",
+		metric.WithDescription("Total number of approval-tier evaluations that returned an unknown variant"),
+	)
+
+	timeInPendingHistogram, _ := meter.Float64Histogram(
+		"admin_time_in_pending_seconds",
+		metric.WithDescription("Time bookings spent pending before the auto-approval worker acted on them"),
+		metric.WithUnit("s"),
+	)
+
+	decisionCacheCounter, _ := meter.Int64Counter(
+		"admin_decision_cache_lookups_total",
+		metric.WithDescription("Total number of worker decision cache lookups, by kind and hit or miss"),
+	)
+
+	enrichmentTimeoutCounter, _ := meter.Int64Counter(
+		"admin_enrichment_timeouts_total",
+		metric.WithDescription("Total number of booking list availability lookups that timed out"),
+	)
+
+	writeVerificationCounter, _ := meter.Int64Counter(
+		"admin_write_verifications_total",
+		metric.WithDescription("Total number of booking updates read back to verify they were persisted, by result"),
+	)
+
+	bookingCacheCounter, _ := meter.Int64Counter(
+		"admin_booking_cache_lookups_total",
+		metric.WithDescription("Total number of booking cache lookups, by hit or miss"),
+	)
+
+	staleAvailabilityCounter, _ := meter.Int64Counter(
+		"admin_stale_availability_decisions_total",
+		metric.WithDescription("Total number of worker decisions made on last-known availability after a failed lookup"),
+	)
+
+	cancellationCounter, _ := meter.Int64Counter(
+		"admin_booking_cancellations_total",
+		metric.WithDescription("Total number of confirmed bookings cancelled because their hotel can't honor them"),
+	)
+
 	service := &AdminService{
-		fliptClient:     fliptClient,
-		hotelClient:     hotelClient,
-		viewCounter:     viewCounter,
-		approvalCounter: approvalCounter,
+		evaluator:                  evaluator,
+		hotelClient:                hotelClient,
+		cfg:                        cfg,
+		auditLog:                   NewAuditLog(cfg.AuditLogSize),
+		hooks:                      hooks,
+		viewCounter:                viewCounter,
+		bookingsReturnedHistogram:  bookingsReturnedHistogram,
+		approvalCounter:            approvalCounter,
+		availabilityTimeoutCounter: availabilityTimeoutCounter,
+		autoApprovalSkipCounter:    autoApprovalSkipCounter,
+		bulkRejectCounter:          bulkRejectCounter,
+		bulkRejectRunCounter:       bulkRejectRunCounter,
+		shadowTierCounter:          shadowTierCounter,
+		unknownTierCounter:         unknownTierCounter,
+		tierFallbackCounter:        tierFallbackCounter,
+		timeInPendingHistogram:     timeInPendingHistogram,
+		decisionCacheCounter:       decisionCacheCounter,
+		enrichmentTimeoutCounter:   enrichmentTimeoutCounter,
+		writeVerificationCounter:   writeVerificationCounter,
+		bookingCacheCounter:        bookingCacheCounter,
+		staleAvailabilityCounter:   staleAvailabilityCounter,
+		cancellationCounter:        cancellationCounter,
+		jobs:                       NewJobStore(cfg.JobStoreSize, cfg.JobTTL),
+		decisions:                  NewDecisionCache(cfg.DecisionCacheTTL, cfg.StaleAvailabilityMaxAge),
+		bookings:                   NewBookingCache(cfg.BookingCacheTTL),
+		events:                     NewEventBus(),
+		tierStats:                  NewTierStats(cfg.TierStatsWindow),
+		reasonCodes:                NewReasonCodeCache(cfg.RejectReasonCodesTTL),
 	}
 
 	return service
 }
 
+// newTenantService returns a service for another tenant's worker, evaluating
+// flags with evaluator in cfg's namespace and listing bookings with
+// hotelClient. It shares s's audit log, event bus, jobs, caches, stats and
+// notifier, so tenant decisions show up in the API like s's own.
+func (s *AdminService) newTenantService(evaluator Evaluator, hotelClient *hotelclient.Client, cfg Config) *AdminService {
+	tenant := NewAdminService(evaluator, hotelClient, cfg, s.hooks)
+	tenant.auditLog = s.auditLog
+	tenant.events = s.events
+	tenant.jobs = s.jobs
+	tenant.decisions = s.decisions
+	tenant.bookings = s.bookings
+	tenant.tierStats = s.tierStats
+	tenant.notifier = s.notifier
+	return tenant
+}
+
+// bookingMetricAttrs returns the per-booking metric attributes. booking_id is
+// unbounded-cardinality, so it is only included when explicitly enabled for
+// debugging; spans always carry it.
+func (s *AdminService) bookingMetricAttrs(bookingID string) []attribute.KeyValue {
+	if !s.cfg.MetricIncludeBookingID {
+		return nil
+	}
+	return []attribute.KeyValue{attribute.String("booking_id", bookingID)}
+}
+
+// evaluationContext merges the process-wide base context, the keys mapped
+// from the request's headers and per-call keys. Later sources take
+// precedence, so a header can't override a booking's own keys.
+func (s *AdminService) evaluationContext(ctx context.Context, keys map[string]string) map[string]string {
+	evalCtx := maps.Clone(s.cfg.EvaluationContext)
+	if evalCtx == nil {
+		evalCtx = map[string]string{}
+	}
+	maps.Copy(evalCtx, headerContextFromContext(ctx))
+	maps.Copy(evalCtx, keys)
+	return evalCtx
+}
+
+// checkReady verifies that Flipt can evaluate flags and the hotel service is
+// reachable.
+func (s *AdminService) checkReady(ctx context.Context) error {
+	_, err := s.evaluator.EvaluateBoolean(ctx, &sdk.EvaluationRequest{
+		FlagKey:  "auto-approval",
+		EntityID: "worker",
+		Context:  s.evaluationContext(ctx, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("flipt: %w", err)
+	}
+
+	if err := s.hotelClient.Ping(ctx); err != nil {
+		return fmt.Errorf("hotel service: %w", err)
+	}
+	return nil
+}
+
 func (s *AdminService) autoApprovalEnabled(ctx context.Context) bool {
 	span := trace.SpanFromContext(ctx)
 	req := &sdk.EvaluationRequest{
 		FlagKey:  "auto-approval",
 		EntityID: "worker",
-		Context:  map[string]string{},
+		Context:  s.evaluationContext(ctx, nil),
 	}
 
-	result, err := s.fliptClient.EvaluateBoolean(ctx, req)
+	result, err := s.evaluator.EvaluateBoolean(ctx, req)
 	if err != nil {
 		log.Printf("Error evaluating auto_approval flag: %v", err)
 		return false
@@ -76,6 +323,197 @@ func (s *AdminService) autoApprovalEnabled(ctx context.Context) bool {
 	return result.Enabled
 }
 
+// killSwitchActive evaluates the kill-switch flag, which halts auto-approval
+// even while the auto-approval flag is enabled. A missing flag counts as
+// inactive without logging, since most namespaces never create it. Other
+// evaluation errors also count as inactive, so an unreachable Flipt falls
+// back on autoApprovalEnabled, which fails closed.
+func (s *AdminService) killSwitchActive(ctx context.Context) bool {
+	if s.cfg.KillSwitchFlagKey == "" {
+		return false
+	}
+
+	span := trace.SpanFromContext(ctx)
+	req := &sdk.EvaluationRequest{
+		FlagKey:  s.cfg.KillSwitchFlagKey,
+		EntityID: "worker",
+		Context:  s.evaluationContext(ctx, nil),
+	}
+
+	result, err := s.evaluator.EvaluateBoolean(ctx, req)
+	if err != nil {
+		if !flagNotFound(err) {
+			log.Printf("Error evaluating %s flag: %v", req.FlagKey, err)
+		}
+		return false
+	}
+
+	span.AddEvent("feature_flag.evaluation", trace.WithAttributes(
+		semconv.FeatureFlagKey(req.FlagKey),
+		semconv.FeatureFlagResultVariant(strconv.FormatBool(result.Enabled)),
+		semconv.FeatureFlagResultReasonKey.String(result.Reason),
+	))
+
+	return result.Enabled
+}
+
+func (s *AdminService) manualReviewRequest(ctx context.Context, booking *hotelclient.Booking) *sdk.EvaluationRequest {
+	return &sdk.EvaluationRequest{
+		FlagKey:  "require-manual-review",
+		EntityID: bookingEntityID(ctx, booking),
+		Context: s.evaluationContext(ctx, map[string]string{
+			"hotel_id":    booking.HotelID,
+			"total_price": fmt.Sprintf("%.2f", booking.TotalPrice),
+			"value_tier":  s.valueTier(booking.TotalPrice),
+			"guests":      strconv.Itoa(booking.Guests),
+		}),
+	}
+}
+
+// adminFeature evaluates the admin feature flag for the authenticated
+// caller, with their user ID as entity and role in the context, so features
+// can be rolled out to specific admins. Unauthenticated callers evaluate as
+// anonymous; errors count as disabled.
+func (s *AdminService) adminFeature(ctx context.Context) AdminFeatureStatus {
+	span := trace.SpanFromContext(ctx)
+	req := &sdk.EvaluationRequest{
+		FlagKey:  s.cfg.AdminFeatureFlagKey,
+		EntityID: anonymousEntityID,
+		Context:  s.evaluationContext(ctx, map[string]string{"role": anonymousEntityID}),
+	}
+	if principal, ok := principalFromContext(ctx); ok {
+		req.EntityID = principal.User
+		req.Context["role"] = principal.Role.String()
+	}
+
+	status := AdminFeatureStatus{FlagKey: req.FlagKey, EntityID: req.EntityID}
+	result, err := s.evaluator.EvaluateBoolean(ctx, req)
+	if err != nil {
+		log.Printf("Error evaluating %s flag: %v", req.FlagKey, err)
+		return status
+	}
+
+	span.AddEvent("feature_flag.evaluation", trace.WithAttributes(
+		semconv.FeatureFlagKey(req.FlagKey),
+		semconv.FeatureFlagResultVariant(strconv.FormatBool(result.Enabled)),
+		semconv.FeatureFlagResultReasonKey.String(result.Reason),
+	))
+
+	status.Enabled = result.Enabled
+	return status
+}
+
+// requireManualReview evaluates the require-manual-review flag for a booking.
+// Rule authors use it to keep specific bookings away from auto-approval;
+// evaluation errors, including a missing flag, count as false.
+func (s *AdminService) requireManualReview(ctx context.Context, booking *hotelclient.Booking) bool {
+	span := trace.SpanFromContext(ctx)
+	req := s.manualReviewRequest(ctx, booking)
+
+	result, err := s.evaluator.EvaluateBoolean(ctx, req)
+	if err != nil {
+		log.Printf("Error evaluating require-manual-review flag: %v", err)
+		return false
+	}
+
+	span.AddEvent("feature_flag.evaluation", trace.WithAttributes(
+		semconv.FeatureFlagKey(req.FlagKey),
+		semconv.FeatureFlagResultVariant(strconv.FormatBool(result.Enabled)),
+		semconv.FeatureFlagResultReasonKey.String(result.Reason),
+	))
+
+	return result.Enabled
+}
+
+// anonymousEntityID is the Flipt entity ID for evaluations without a guest,
+// such as the flag preview in GetApiFlags.
+const anonymousEntityID = "anonymous"
+
+// bookingEntityID returns the Flipt entity ID for a booking: the guest email,
+// falling back to the booking ID so percentage rollouts still bucket the
+// booking consistently, and to anonymousEntityID without either.
+func bookingEntityID(ctx context.Context, booking *hotelclient.Booking) string {
+	if booking.GuestEmail != "" {
+		return booking.GuestEmail
+	}
+	if booking.BookingID == "" {
+		return anonymousEntityID
+	}
+
+	log.Printf("Warning: booking %s has no guest email, evaluating flags by booking ID", booking.BookingID)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("entity_id_fallback", true))
+	return booking.BookingID
+}
+
+// approvalTierOverride returns the tier configured in APPROVAL_TIER_OVERRIDES
+// for the booking's guest. Emails are matched case-insensitively, and
+// overrides to tiers outside APPROVAL_KNOWN_TIERS are ignored.
+func (s *AdminService) approvalTierOverride(booking *hotelclient.Booking) (string, bool) {
+	if booking.GuestEmail == "" {
+		return "", false
+	}
+	for email, tier := range s.cfg.ApprovalTierOverrides {
+		if !strings.EqualFold(email, booking.GuestEmail) {
+			continue
+		}
+		if !slices.Contains(s.cfg.ApprovalKnownTiers, tier) {
+			log.Printf("Warning: ignoring override to unknown approval tier %q for %s", tier, booking.GuestEmail)
+			return "", false
+		}
+		return tier, true
+	}
+	return "", false
+}
+
+func (s *AdminService) approvalTierRequest(ctx context.Context, booking *hotelclient.Booking) *sdk.EvaluationRequest {
+	return &sdk.EvaluationRequest{
+		FlagKey:  "approval-tier",
+		EntityID: bookingEntityID(ctx, booking),
+		Context: s.evaluationContext(ctx, map[string]string{
+			"hotel_id":    booking.HotelID,
+			"total_price": fmt.Sprintf("%.2f", booking.TotalPrice),
+			"value_tier":  s.valueTier(booking.TotalPrice),
+		}),
+	}
+}
+
+// evaluateTierFallbacks evaluates the APPROVAL_TIER_FALLBACK_FLAGS in order
+// with the entity and context of the approval-tier request req, after it
+// matched no variant. The first variant found wins; flags that fail to
+// evaluate are skipped. The flag that produced the tier is recorded on the
+// span and counted, with "none" when the chain is exhausted, in which case
+// the empty variant is returned.
+func (s *AdminService) evaluateTierFallbacks(ctx context.Context, req *sdk.EvaluationRequest) *sdk.VariantEvaluationResponse {
+	span := trace.SpanFromContext(ctx)
+
+	for _, flagKey := range s.cfg.ApprovalTierFallbackFlags {
+		fallbackReq := *req
+		fallbackReq.FlagKey = flagKey
+		result, err := s.evaluator.EvaluateVariant(ctx, &fallbackReq)
+		if err != nil {
+			log.Printf("Warning: skipping approval-tier fallback flag %s: %v", flagKey, err)
+			span.RecordError(err)
+			continue
+		}
+
+		span.AddEvent("feature_flag.evaluation", trace.WithAttributes(
+			semconv.FeatureFlagKey(flagKey),
+			semconv.FeatureFlagResultVariant(result.VariantKey),
+			semconv.FeatureFlagResultReasonKey.String(result.Reason),
+		))
+		if result.VariantKey != "" {
+			span.SetAttributes(attribute.String("approval_tier_flag", flagKey))
+			metricsFromContext(ctx).Add(ctx, s.tierFallbackCounter, 1, attribute.String("flag", flagKey))
+			return result
+		}
+	}
+
+	log.Printf("Warning: no approval-tier fallback flag matched for %s", req.EntityID)
+	span.SetAttributes(attribute.String("approval_tier_flag", "none"))
+	metricsFromContext(ctx).Add(ctx, s.tierFallbackCounter, 1, attribute.String("flag", "none"))
+	return &sdk.VariantEvaluationResponse{}
+}
+
 func (s *AdminService) evaluateApprovalRules(ctx context.Context, booking *hotelclient.Booking) (string, error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
@@ -84,18 +522,24 @@ func (s *AdminService) evaluateApprovalRules(ctx context.Context, booking *hotel
 		attribute.Float64("total_price", booking.TotalPrice),
 	)
 
-	req := &sdk.EvaluationRequest{
-		FlagKey:  "approval-tier",
-		EntityID: booking.GuestEmail,
-		Context: map[string]string{
-			"hotel_id":    booking.HotelID,
-			"total_price": fmt.Sprintf("%.2f", booking.TotalPrice),
-		},
+	if tier, ok := s.approvalTierOverride(booking); ok {
+		log.Printf("Approval tier for %s overridden to %s", booking.GuestEmail, tier)
+		span.SetAttributes(attribute.String("tier_override", tier))
+		return tier, nil
 	}
-	approvalTier, err := s.fliptClient.EvaluateVariant(ctx, req)
+
+	req := s.approvalTierRequest(ctx, booking)
+	approvalTier, err := s.evaluator.EvaluateVariant(ctx, req)
 	if err != nil {
 		log.Printf("Error evaluating approval-tier flag: %v", err)
 		span.RecordError(err)
+
+		// A slow evaluation that used up only its own timeout falls back to
+		// the default tier so the request can still finish within its budget
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			span.SetAttributes(attribute.Bool("evaluation_timeout", true))
+			return s.cfg.ApprovalDefaultTier, nil
+		}
 		return "", err
 	}
 
@@ -105,39 +549,167 @@ func (s *AdminService) evaluateApprovalRules(ctx context.Context, booking *hotel
 		semconv.FeatureFlagResultReasonKey.String(approvalTier.Reason),
 	))
 
+	if s.cfg.ShadowApprovalTierFlagKey != "" {
+		go s.shadowEvaluateTier(context.WithoutCancel(ctx), req, approvalTier.VariantKey)
+	}
+
+	if len(s.cfg.ApprovalTierFallbackFlags) > 0 {
+		if approvalTier.VariantKey == "" {
+			approvalTier = s.evaluateTierFallbacks(ctx, req)
+		} else {
+			span.SetAttributes(attribute.String("approval_tier_flag", req.FlagKey))
+		}
+	}
+
+	// Keep a misconfigured flag from feeding unknown tiers into tier-based
+	// business logic
+	if !slices.Contains(s.cfg.ApprovalKnownTiers, approvalTier.VariantKey) {
+		log.Printf("Warning: approval-tier returned unknown variant %q, using %q", approvalTier.VariantKey, s.cfg.ApprovalDefaultTier)
+		span.SetAttributes(attribute.String("unknown_tier", approvalTier.VariantKey))
+		metricsFromContext(ctx).Add(ctx, s.unknownTierCounter, 1,
+			attribute.String("variant", approvalTier.VariantKey),
+		)
+		return s.cfg.ApprovalDefaultTier, nil
+	}
+
 	return approvalTier.VariantKey, nil
 }
 
+// shadowEvaluateTier evaluates the candidate approval-tier flag with the same
+// request and records how it compares to the tier actually used. The result
+// is never acted upon.
+func (s *AdminService) shadowEvaluateTier(ctx context.Context, req *sdk.EvaluationRequest, primary string) {
+	shadowReq := *req
+	shadowReq.FlagKey = s.cfg.ShadowApprovalTierFlagKey
+
+	shadow, err := s.evaluator.EvaluateVariant(ctx, &shadowReq)
+	if err != nil {
+		log.Printf("Error evaluating shadow flag %s: %v", shadowReq.FlagKey, err)
+		return
+	}
+
+	match := shadow.VariantKey == primary
+	if !match {
+		log.Printf("Shadow flag %s diverged for entity %s: primary=%s shadow=%s", shadowReq.FlagKey, req.EntityID, primary, shadow.VariantKey)
+	}
+
+	metricsFromContext(ctx).Add(ctx, s.shadowTierCounter, 1,
+		attribute.String("flag_key", shadowReq.FlagKey),
+		attribute.String("primary_tier", primary),
+		attribute.String("shadow_tier", shadow.VariantKey),
+		attribute.Bool("match", match),
+	)
+}
+
 func (s *AdminService) GetHealth(w http.ResponseWriter, r *http.Request) {
-	respondJSON(w, http.StatusOK, map[string]string{"status": "healthy", "service": "admin-service"})
+	respondJSON(w, http.StatusOK, HealthResponse{Status: "healthy", Service: "admin-service"})
+}
+
+func (s *AdminService) GetReady(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkReady(r.Context()); err != nil {
+		respondJSON(w, http.StatusServiceUnavailable, ReadinessResponse{Status: "not ready", Error: err.Error()})
+		return
+	}
+	respondJSON(w, http.StatusOK, ReadinessResponse{Status: "ready"})
 }
 
 func (s *AdminService) GetApiBookings(w http.ResponseWriter, r *http.Request, params api.GetApiBookingsParams) {
-	ctx, span := tracer.Start(r.Context(), "get_bookings")
+	ctx, span := startHandlerSpan(r, "get_bookings")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("api_version", apiVersion1))
+
+	list, ok := s.listBookings(ctx, w, r, bookingListQuery{
+		status:              queryParam(params.Status),
+		includeAvailability: params.IncludeAvailability != nil && *params.IncludeAvailability,
+		sort:                queryParam(params.Sort),
+		order:               queryParam(params.Order),
+	})
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, list)
+}
+
+// GetApiV2Bookings lists bookings like GetApiBookings, wrapped in the v2
+// response envelope
+func (s *AdminService) GetApiV2Bookings(w http.ResponseWriter, r *http.Request, params api.GetApiV2BookingsParams) {
+	ctx, span := startHandlerSpan(r, "get_bookings")
 	defer span.End()
 
-	status := ""
-	if params.Status != nil {
-		status = string(*params.Status)
+	span.SetAttributes(attribute.String("api_version", apiVersion2))
+
+	list, ok := s.listBookings(ctx, w, r, bookingListQuery{
+		status:              queryParam(params.Status),
+		includeAvailability: params.IncludeAvailability != nil && *params.IncludeAvailability,
+		sort:                queryParam(params.Sort),
+		order:               queryParam(params.Order),
+	})
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, Envelope[BookingListResponse]{Version: apiVersion2, Data: list})
+}
+
+// listBookings fetches the bookings for every version of the list endpoint.
+// On failure it writes the error response and returns false.
+func (s *AdminService) listBookings(ctx context.Context, w http.ResponseWriter, r *http.Request, query bookingListQuery) (BookingListResponse, bool) {
+	status := query.status
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("status_filter", status),
+		attribute.String("sort", query.sort),
+		attribute.String("order", query.order),
+	)
+
+	if !validStatusFilter(status) {
+		respondError(w, r, http.StatusBadRequest, invalidStatusMessage)
+		return BookingListResponse{}, false
+	}
+	if !validSort(query.sort, query.order) {
+		respondError(w, r, http.StatusBadRequest, invalidSortMessage)
+		return BookingListResponse{}, false
 	}
-	span.SetAttributes(attribute.String("status_filter", status))
 
 	// Fetch bookings from hotel-service using client
 	bookings, err := s.getBookings(ctx, status)
 	if err != nil {
 		log.Printf("Error fetching bookings from hotel-service: %v", err)
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch bookings"})
-		return
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch bookings")
+		return BookingListResponse{}, false
 	}
 
 	log.Printf("Retrieved %d bookings with status=%s from hotel-service", len(bookings), status)
+	sortBookingList(bookings, query.sort, query.order)
 
-	respondJSON(w, http.StatusOK, map[string]any{
-		"bookings": bookings,
-		"total":    len(bookings),
-		"status":   status,
-	})
+	var listed []ListedBooking
+	if query.includeAvailability {
+		listed = s.enrichBookings(ctx, bookings)
+	} else {
+		listed = make([]ListedBooking, len(bookings))
+		for i, booking := range bookings {
+			listed[i].Booking = booking
+		}
+	}
+
+	for i := range listed {
+		listed[i].ValueTier = s.valueTier(listed[i].TotalPrice)
+	}
+
+	return BookingListResponse{
+		Bookings: listed,
+		Total:    len(bookings),
+		Status:   status,
+	}, true
+}
+
+var invalidStatusMessage = "Invalid status; must be one of: " + strings.Join(bookingStatuses, ", ")
+
+// validStatusFilter reports whether status is a known booking status. An
+// empty status means all bookings.
+func validStatusFilter(status string) bool {
+	return status == "" || slices.Contains(bookingStatuses, status)
 }
 
 func (s *AdminService) getBookings(ctx context.Context, status string) ([]hotelclient.Booking, error) {
@@ -146,118 +718,254 @@ func (s *AdminService) getBookings(ctx context.Context, status string) ([]hotelc
 		return nil, err
 	}
 
-	s.viewCounter.Add(ctx, 1, metric.WithAttributes(
-		attribute.String("status", status),
-		attribute.Int("count", len(bookings)),
-	))
+	m := metricsFromContext(ctx)
+	m.Add(ctx, s.viewCounter, 1, attribute.String("status", status))
+	m.Record(ctx, s.bookingsReturnedHistogram, int64(len(bookings)), attribute.String("status", status))
 	return bookings, nil
 }
 
+// getBookingsPage fetches up to limit bookings with a status from offset,
+// and the total number matching, recording the same metrics as getBookings.
+func (s *AdminService) getBookingsPage(ctx context.Context, status string, offset, limit int) ([]hotelclient.Booking, int, error) {
+	page, err := s.hotelClient.GetBookingsPage(ctx, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m := metricsFromContext(ctx)
+	m.Add(ctx, s.viewCounter, 1, attribute.String("status", status))
+	m.Record(ctx, s.bookingsReturnedHistogram, int64(len(page.Bookings)), attribute.String("status", status))
+	return page.Bookings, page.Total, nil
+}
+
 func (s *AdminService) GetApiBookingsBookingId(w http.ResponseWriter, r *http.Request, bookingID string) {
-	ctx, span := tracer.Start(r.Context(), "get_booking")
+	ctx, span := startHandlerSpan(r, "get_booking")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("booking_id", bookingID))
 
 	// Fetch specific booking from hotel-service using client
-	booking, err := s.hotelClient.GetBooking(ctx, bookingID)
+	booking, err := s.getBooking(ctx, bookingID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, hotelclient.ErrNotFound) {
 			span.SetAttributes(attribute.Bool("found", false))
-			respondJSON(w, http.StatusNotFound, map[string]string{"error": "Booking not found"})
+			respondError(w, r, http.StatusNotFound, "Booking not found")
 			return
 		}
 		log.Printf("Error fetching booking from hotel-service: %v", err)
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch booking"})
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch booking")
 		return
 	}
 
 	span.SetAttributes(attribute.Bool("found", true))
-	s.viewCounter.Add(ctx, 1, metric.WithAttributes(
-		attribute.String("booking_id", bookingID),
-	))
+	metricsFromContext(ctx).Add(ctx, s.viewCounter, 1, s.bookingMetricAttrs(bookingID)...)
 
-	respondJSON(w, http.StatusOK, booking)
+	// Confirmed and rejected bookings are terminal and safe to cache briefly;
+	// pending bookings may be decided at any moment.
+	if booking.Status == "pending" {
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(terminalBookingMaxAge.Seconds())))
+	}
+
+	respondJSON(w, http.StatusOK, ListedBooking{
+		Booking:   *booking,
+		ValueTier: s.valueTier(booking.TotalPrice),
+	})
 }
 
-func (s *AdminService) PostApiBookingsBookingIdApprove(w http.ResponseWriter, r *http.Request, bookingID string) {
-	ctx, span := tracer.Start(r.Context(), "approve_booking")
+func (s *AdminService) PostApiBookingsBookingIdApprove(w http.ResponseWriter, r *http.Request, bookingID string, params api.PostApiBookingsBookingIdApproveParams) {
+	ctx, span := startHandlerSpan(r, "approve_booking")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("booking_id", bookingID))
 
+	// The body is optional; an empty body approves without a note
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxApprovalBodyBytes)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if utf8.RuneCountInString(req.Note) > approvalNoteMaxLength {
+		respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Note must be at most %d characters", approvalNoteMaxLength))
+		return
+	}
+	if req.Note != "" {
+		span.SetAttributes(attribute.String("note", req.Note))
+	}
+
 	// Fetch the specific booking from hotel-service using client
-	booking, err := s.hotelClient.GetBooking(ctx, bookingID)
+	booking, err := s.getBookingForUpdate(ctx, bookingID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, hotelclient.ErrNotFound) {
 			span.SetAttributes(attribute.Bool("found", false))
-			respondJSON(w, http.StatusNotFound, map[string]string{"error": "Booking not found"})
+			respondError(w, r, http.StatusNotFound, "Booking not found")
 			return
 		}
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch booking"})
+		respondDecisionError(w, r, DecisionUpstreamError, "Failed to fetch booking")
 		return
 	}
 	if s.autoApprovalEnabled(ctx) {
 		span.RecordError(errAutoApprovalEnabled)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": errAutoApprovalEnabled.Error()})
+		respondDecisionError(w, r, DecisionAutoApprovalActive, errAutoApprovalEnabled.Error())
+		return
+	}
+
+	// Very old pending bookings need a conscious decision to approve.
+	// Bookings without a creation timestamp are never too old.
+	if age, ok := pendingAge(booking); ok {
+		span.SetAttributes(attribute.Float64("booking_age_seconds", age.Seconds()))
+		limit := s.cfg.MaxManualApproveAge
+		if limit > 0 && age > limit && booking.Status == "pending" {
+			override := params.OverrideMaxAge != nil && *params.OverrideMaxAge
+			span.SetAttributes(attribute.Bool("override_max_age", override))
+			if !override {
+				respondError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf(
+					"Booking has been pending for %s, longer than the %s limit; set override_max_age to approve it anyway",
+					age.Round(time.Second), limit))
+				return
+			}
+			log.Printf("Manual approval of booking %s pending for %s overrides the %s age limit", bookingID, age.Round(time.Second), limit)
+		}
+	}
+
+	includeAvailability := params.IncludeAvailability != nil && *params.IncludeAvailability
+
+	// Hand slow hotel-service interactions to a background job and let the
+	// client poll for the outcome
+	if s.cfg.AsyncApproval {
+		job, err := s.jobs.Create(bookingID)
+		if err != nil {
+			span.RecordError(err)
+			respondError(w, r, http.StatusServiceUnavailable, "Too many approvals in progress")
+			return
+		}
+		span.SetAttributes(attribute.String("job_id", job.ID))
+
+		s.jobs.Run(ctx, job.ID, func(ctx context.Context) (*BookingDecisionResponse, error) {
+			resp, err := s.manualApprove(ctx, booking, includeAvailability, req.Note)
+			if err != nil {
+				log.Printf("Hotel service error when updating booking in job %s: %v", job.ID, err)
+			}
+			return resp, err
+		})
+
+		w.Header().Set("Location", job.StatusURL)
+		respondJSON(w, http.StatusAccepted, job)
 		return
 	}
 
-	err = s.approveBooking(ctx, booking, false)
+	resp, err := s.manualApprove(ctx, booking, includeAvailability, req.Note)
 	if err != nil {
 		log.Printf("Hotel service error when updating booking: %v", err)
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to confirm booking"})
+		respondDecisionError(w, r, decisionOutcome(err), "Failed to confirm booking")
 		return
 	}
+	respondJSON(w, http.StatusOK, resp)
+}
 
-	respondJSON(w, http.StatusOK, map[string]any{
-		"booking_id": bookingID,
-		"status":     "confirmed",
-		"message":    "Booking approved and confirmed successfully",
-	})
+// pendingAge returns how long a booking has existed. ok is false when its
+// creation timestamp is missing or can't be parsed.
+func pendingAge(booking *hotelclient.Booking) (age time.Duration, ok bool) {
+	created, err := booking.Created()
+	if err != nil {
+		return 0, false
+	}
+	return timeNow().Sub(created), true
+}
+
+// manualApprove approves a booking on behalf of an operator, optionally
+// recording the hotel's current availability and the operator's note
+// alongside the decision.
+func (s *AdminService) manualApprove(ctx context.Context, booking *hotelclient.Booking, includeAvailability bool, note string) (*BookingDecisionResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	var hotel *hotelclient.HotelInfo
+	if includeAvailability {
+		var err error
+		hotel, err = s.getHotelAvailability(ctx, booking)
+		if err != nil {
+			log.Printf("Error fetching availability for hotel %s: %v", booking.HotelID, err)
+			span.RecordError(err)
+		}
+	}
+
+	approval, err := s.approveBooking(ctx, booking, hotel, note, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BookingDecisionResponse{
+		BookingID:             booking.BookingID,
+		Status:                "confirmed",
+		Outcome:               DecisionApproved,
+		Message:               "Booking approved and confirmed successfully",
+		Note:                  note,
+		ConfirmationExpiresAt: &approval.ConfirmationExpiresAt,
+	}
+	if hotel != nil {
+		resp.AvailableRooms = &hotel.AvailableRooms
+	}
+	return resp, nil
 }
 
 func (s *AdminService) PostApiBookingsBookingIdReject(w http.ResponseWriter, r *http.Request, bookingID string) {
-	ctx, span := tracer.Start(r.Context(), "reject_booking")
+	ctx, span := startHandlerSpan(r, "reject_booking")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("booking_id", bookingID))
 
 	var req struct {
-		Reason string `json:"reason"`
+		Reason     string `json:"reason"`
+		ReasonCode string `json:"reason_code"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
+	// Reason codes are optional, but must come from the centrally managed
+	// list when given
+	reasonKey := reasonManual
+	if req.ReasonCode != "" {
+		span.SetAttributes(attribute.String("reason_code", req.ReasonCode))
+		allowed := s.allowedReasonCodes(ctx)
+		if !slices.Contains(allowed, req.ReasonCode) {
+			respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Unknown reason_code %q, expected one of: %s", req.ReasonCode, strings.Join(allowed, ", ")))
+			return
+		}
+		reasonKey = req.ReasonCode
+	}
+
 	// Fetch specific booking from hotel-service to verify it exists and check status
-	booking, err := s.hotelClient.GetBooking(ctx, bookingID)
+	booking, err := s.getBookingForUpdate(ctx, bookingID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, hotelclient.ErrNotFound) {
 			span.SetAttributes(attribute.Bool("found", false))
-			respondJSON(w, http.StatusNotFound, map[string]string{"error": "Booking not found"})
+			respondError(w, r, http.StatusNotFound, "Booking not found")
 			return
 		}
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch booking"})
+		respondDecisionError(w, r, DecisionUpstreamError, "Failed to fetch booking")
 		return
 	}
 
 	if s.autoApprovalEnabled(ctx) {
 		span.RecordError(errAutoApprovalEnabled)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": errAutoApprovalEnabled.Error()})
+		respondDecisionError(w, r, DecisionAutoApprovalActive, errAutoApprovalEnabled.Error())
 		return
 	}
 
-	err = s.rejectBooking(ctx, booking, req.Reason, false)
+	err = s.rejectBooking(ctx, booking, reasonKey, req.Reason, false)
 	if err != nil {
 		log.Printf("Hotel service error when updating booking: %v", err)
 		span.RecordError(err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to reject booking"})
+		respondDecisionError(w, r, decisionOutcome(err), "Failed to reject booking")
 		return
 	}
 
@@ -265,111 +973,493 @@ func (s *AdminService) PostApiBookingsBookingIdReject(w http.ResponseWriter, r *
 		attribute.String("reason", req.Reason),
 	)
 
-	respondJSON(w, http.StatusOK, map[string]any{
-		"booking_id": bookingID,
-		"status":     "rejected",
-		"message":    "Booking rejected successfully",
-		"reason":     req.Reason,
+	respondJSON(w, http.StatusOK, BookingDecisionResponse{
+		BookingID: bookingID,
+		Status:    "rejected",
+		Outcome:   DecisionRejected,
+		Message:   "Booking rejected successfully",
+		Reason:    req.Reason,
 	})
 }
 
-func (s *AdminService) GetApiFlags(w http.ResponseWriter, r *http.Request) {
-	ctx, span := tracer.Start(r.Context(), "get_flag_status")
+func (s *AdminService) GetApiBookingsBookingIdAudit(w http.ResponseWriter, r *http.Request, bookingID string, params api.GetApiBookingsBookingIdAuditParams) {
+	_, span := startHandlerSpan(r, "get_booking_audit")
 	defer span.End()
 
-	autoApprovalEnabled := s.autoApprovalEnabled(ctx)
-	approvalTier, err := s.evaluateApprovalRules(ctx, &hotelclient.Booking{})
-	if err != nil {
-		log.Printf("Error evaluating approval-tier: %v", err)
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to get flag status"})
+	span.SetAttributes(attribute.String("booking_id", bookingID))
+
+	limit, offset := auditDefaultLimit, 0
+	if params.Limit != nil {
+		limit = min(*params.Limit, auditMaxLimit)
+	}
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if limit < 1 || offset < 0 {
+		respondError(w, r, http.StatusBadRequest, "limit must be at least 1 and offset at least 0")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]any{
-		"auto_approval": map[string]any{
-			"enabled": autoApprovalEnabled,
-		},
-		"approval_tier": map[string]any{
-			"variant": approvalTier,
-		},
+	entries := s.auditLog.ForBooking(bookingID)
+	span.SetAttributes(attribute.Int("entries", len(entries)))
+	if len(entries) == 0 {
+		respondError(w, r, http.StatusNotFound, "No audit history for booking")
+		return
+	}
+
+	// Page backwards from the most recent entry, keeping oldest-first order
+	end := max(len(entries)-offset, 0)
+	start := max(end-limit, 0)
+	span.SetAttributes(attribute.Bool("truncated", start > 0))
+
+	w.Header().Set("X-Truncated", strconv.FormatBool(start > 0))
+	respondJSON(w, http.StatusOK, AuditHistoryResponse{
+		BookingID: bookingID,
+		Entries:   entries[start:end],
+		Total:     len(entries),
 	})
 }
 
-func (s *AdminService) processBooking(ctx context.Context, booking *hotelclient.Booking) error {
+func (s *AdminService) GetApiFlags(w http.ResponseWriter, r *http.Request, params api.GetApiFlagsParams) {
+	ctx, span := startHandlerSpan(r, "get_flag_status")
+	defer span.End()
+
+	autoApprovalEnabled := s.autoApprovalEnabled(ctx)
+	adminFeature := s.adminFeature(ctx)
+
+	// The approval tier depends on the booking, so it is only evaluated for
+	// a preview booking described by the query; a tier for an empty booking
+	// would say nothing about real ones
+	approvalTier := ApprovalTierStatus{FlagKey: "approval-tier"}
+	if params.HotelId != nil || params.TotalPrice != nil {
+		preview := &hotelclient.Booking{}
+		if params.HotelId != nil {
+			preview.HotelID = *params.HotelId
+		}
+		if params.TotalPrice != nil {
+			preview.TotalPrice = float64(*params.TotalPrice)
+		}
+		variant, err := s.evaluateApprovalRules(ctx, preview)
+		if err != nil {
+			log.Printf("Error evaluating approval-tier: %v", err)
+			respondError(w, r, http.StatusInternalServerError, "Failed to get flag status")
+			return
+		}
+		approvalTier.Evaluated = true
+		approvalTier.Variant = variant
+	}
+	span.SetAttributes(attribute.Bool("approval_tier_preview", approvalTier.Evaluated))
+
+	respondJSON(w, http.StatusOK, FlagStatusResponse{
+		AutoApproval: AutoApprovalStatus{Enabled: autoApprovalEnabled},
+		ApprovalTier: approvalTier,
+		AdminFeature: adminFeature,
+		Evaluations:  evaluationDiagnostics(ctx),
+	})
+}
+
+// processBooking lets the auto-approval worker decide a pending booking and
+// returns the outcome: approved, rejected, or skipped when it was left
+// pending.
+func (s *AdminService) processBooking(ctx context.Context, booking *hotelclient.Booking) (string, error) {
 	ctx, span := tracer.Start(ctx, "process_booking")
 	defer span.End()
-	// Fetch hotel details to check available rooms using hotel client
-	hotel, err := s.hotelClient.GetHotelAvailability(ctx, booking.HotelID, booking.Checkin, booking.Checkout, booking.Guests)
+
+	span.SetAttributes(attribute.String("booking_id", booking.BookingID))
+
+	if slices.Contains(s.cfg.HotelDenylist, booking.HotelID) {
+		log.Printf("Skipping booking %s - hotel %s is on the deny-list and requires manual review", booking.BookingID, booking.HotelID)
+		s.skipAutoApproval(ctx, booking, "hotel_denylisted")
+		return outcomeSkipped, nil
+	}
+
+	if len(s.cfg.HotelAllowlist) > 0 && !slices.Contains(s.cfg.HotelAllowlist, booking.HotelID) {
+		log.Printf("Skipping booking %s - hotel %s is not on the allow-list and requires manual review", booking.BookingID, booking.HotelID)
+		s.skipAutoApproval(ctx, booking, "hotel_not_allowlisted")
+		return outcomeSkipped, nil
+	}
+
+	// Hotel policy rejects undersized bookings outright rather than leaving
+	// them for review
+	if minGuests, ok := s.cfg.HotelMinGuests[booking.HotelID]; ok {
+		span.SetAttributes(
+			attribute.Int("min_guests", minGuests),
+			attribute.Int("guests", booking.Guests),
+		)
+		if booking.Guests < minGuests {
+			log.Printf("Rejecting booking %s - %d guests is below hotel %s's minimum of %d", booking.BookingID, booking.Guests, booking.HotelID, minGuests)
+			if err := s.rejectBooking(ctx, booking, reasonBelowMinGuests, s.localize(ctx, booking, reasonBelowMinGuests), true); err != nil {
+				return "", err
+			}
+			s.recordTimeInPending(ctx, booking, outcomeRejected)
+			return outcomeRejected, nil
+		}
+	}
+
+	if s.leadTimeWindowEnabled() {
+		days, ok, err := s.withinLeadTimeWindow(booking)
+		if err != nil {
+			log.Printf("Skipping booking %s - checkin %q can't be checked against the lead time window: %v", booking.BookingID, booking.Checkin, err)
+			s.skipAutoApproval(ctx, booking, "lead_time_unknown")
+			return outcomeSkipped, nil
+		}
+		span.SetAttributes(
+			attribute.Int("lead_days", days),
+			attribute.Bool("within_lead_time_window", ok),
+		)
+		if !ok {
+			log.Printf("Skipping booking %s - checkin is %d days out, outside the auto-approval window of %d to %d days", booking.BookingID, days, s.cfg.AutoApprovalMinLeadDays, s.cfg.AutoApprovalMaxLeadDays)
+			s.skipAutoApproval(ctx, booking, "lead_time_window")
+			return outcomeSkipped, nil
+		}
+	}
+
+	if s.requireManualReview(ctx, booking) {
+		log.Printf("Skipping booking %s - require-manual-review flag is enabled for it", booking.BookingID)
+		s.skipAutoApproval(ctx, booking, "manual_review_required")
+		return outcomeSkipped, nil
+	}
+
+	// Fetch hotel details to check available rooms using hotel client,
+	// reusing the availability seen on an earlier tick while it is cached
+	hotel, err := s.cachedHotelAvailability(ctx, booking)
+	stale := false
 	if err != nil {
+		if known, ok := s.staleAvailability(ctx, booking, err); ok {
+			hotel, err, stale = known, nil, true
+		}
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			// A slow availability lookup should not stall the sweep or reject
+			// the booking; leave it pending for the next tick.
+			log.Printf("Skipping booking %s - availability check for hotel %s timed out", booking.BookingID, booking.HotelID)
+			span.SetAttributes(attribute.Bool("availability_timeout", true))
+			metricsFromContext(ctx).Add(ctx, s.availabilityTimeoutCounter, 1,
+				attribute.String("hotel_id", booking.HotelID),
+			)
+			return outcomeSkipped, nil
+		}
 		log.Printf("Error fetching hotel %s: %v", booking.HotelID, err)
-		return err
+		return "", err
+	}
+
+	// Keep the last rooms for manual review instead of selling them
+	// automatically; only a fully booked hotel is auto-rejected
+	buffer := s.cfg.MinAvailabilityBuffer
+	span.SetAttributes(
+		attribute.Int("availability_buffer", buffer),
+		attribute.Bool("above_availability_buffer", hotel.AvailableRooms > buffer),
+	)
+	if hotel.AvailableRooms > 0 && hotel.AvailableRooms <= buffer {
+		log.Printf("Skipping booking %s - hotel %s has %d available rooms, within the buffer of %d", booking.BookingID, hotel.ID, hotel.AvailableRooms, buffer)
+		s.skipAutoApproval(ctx, booking, "availability_buffer")
+		return outcomeSkipped, nil
 	}
 
 	// Check if hotel has available rooms
-	if hotel.AvailableRooms > 0 {
+	if hotel.AvailableRooms > buffer {
 		log.Printf("Approving booking %s - hotel %s has %d available rooms", booking.BookingID, hotel.ID, hotel.AvailableRooms)
-		return s.approveBooking(ctx, booking, true)
+		if _, err := s.approveBooking(ctx, booking, hotel, "", true); err != nil {
+			return "", err
+		}
+		if stale {
+			metricsFromContext(ctx).Add(ctx, s.staleAvailabilityCounter, 1,
+				attribute.String("hotel_id", booking.HotelID),
+			)
+		}
+		s.recordTimeInPending(ctx, booking, outcomeApproved)
+		return outcomeApproved, nil
+	}
+
+	// Last-known availability is only trusted to approve; a booking isn't
+	// rejected on data the hotel service couldn't confirm
+	if stale {
+		log.Printf("Skipping booking %s - last-known availability for hotel %s shows no rooms", booking.BookingID, hotel.ID)
+		s.skipAutoApproval(ctx, booking, "stale_availability")
+		return outcomeSkipped, nil
 	}
 
 	log.Printf("Rejecting booking %s - hotel %s has no available rooms", booking.BookingID, hotel.ID)
-	return s.rejectBooking(ctx, booking, "No rooms available", true)
+	if err := s.rejectBooking(ctx, booking, reasonNoAvailability, s.localize(ctx, booking, reasonNoAvailability), true); err != nil {
+		return "", err
+	}
+	s.recordTimeInPending(ctx, booking, outcomeRejected)
+	return outcomeRejected, nil
+}
+
+// recordTimeInPending records how long a booking waited before the worker
+// decided it. Bookings without a parseable creation timestamp are skipped.
+func (s *AdminService) recordTimeInPending(ctx context.Context, booking *hotelclient.Booking, outcome string) {
+	created, err := booking.Created()
+	if err != nil {
+		return
+	}
+	metricsFromContext(ctx).RecordFloat(ctx, s.timeInPendingHistogram, timeNow().Sub(created).Seconds(),
+		attribute.String("outcome", outcome),
+	)
+}
+
+// cachedHotelAvailability returns the availability cached for a booking by
+// an earlier tick, or looks it up and caches it.
+func (s *AdminService) cachedHotelAvailability(ctx context.Context, booking *hotelclient.Booking) (*hotelclient.HotelInfo, error) {
+	if d, ok := s.decisions.Get(booking); ok && d.hotel != nil {
+		s.recordDecisionCacheLookup(ctx, "availability", true)
+		return d.hotel, nil
+	}
+	s.recordDecisionCacheLookup(ctx, "availability", false)
+
+	return s.getHotelAvailability(ctx, booking)
+}
+
+// approvalTier evaluates the booking's approval tier. Worker decisions reuse
+// a tier cached by an earlier tick; manual approvals always evaluate.
+func (s *AdminService) approvalTier(ctx context.Context, booking *hotelclient.Booking, autoApproval bool) (string, error) {
+	if !autoApproval {
+		return s.evaluateApprovalRules(ctx, booking)
+	}
+
+	if d, ok := s.decisions.Get(booking); ok && d.tier != "" {
+		s.recordDecisionCacheLookup(ctx, "tier", true)
+		return d.tier, nil
+	}
+	s.recordDecisionCacheLookup(ctx, "tier", false)
+
+	tier, err := s.evaluateApprovalRules(ctx, booking)
+	if err != nil {
+		return "", err
+	}
+	s.decisions.SetTier(booking, tier)
+	return tier, nil
+}
+
+func (s *AdminService) recordDecisionCacheLookup(ctx context.Context, kind string, hit bool) {
+	if !s.decisions.enabled() {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	metricsFromContext(ctx).Add(ctx, s.decisionCacheCounter, 1,
+		attribute.String("kind", kind),
+		attribute.String("result", result),
+	)
+}
+
+// skipAutoApproval records that the worker left a booking pending for manual
+// review instead of deciding it.
+func (s *AdminService) skipAutoApproval(ctx context.Context, booking *hotelclient.Booking, reason string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("skip_reason", reason))
+	metricsFromContext(ctx).Add(ctx, s.autoApprovalSkipCounter, 1,
+		attribute.String("hotel_id", booking.HotelID),
+		attribute.String("reason", reason),
+	)
 }
 
-func (s *AdminService) approveBooking(ctx context.Context, booking *hotelclient.Booking, autoApproval bool) error {
+// getHotelAvailability checks availability for the booking's hotel and dates,
+// bounded by the dedicated availability timeout, and caches the result for
+// later ticks.
+func (s *AdminService) getHotelAvailability(ctx context.Context, booking *hotelclient.Booking) (*hotelclient.HotelInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.HotelAvailabilityTimeout)
+	defer cancel()
+
+	hotel, err := s.hotelClient.GetHotelAvailability(ctx, booking.HotelID, booking.Checkin, booking.Checkout, booking.Guests)
+	if err != nil {
+		return nil, err
+	}
+	s.decisions.SetHotel(booking, hotel)
+	return hotel, nil
+}
+
+// staleAvailability returns the last-known availability for a booking whose
+// lookup failed with err, if StaleAvailabilityMaxAge allows deciding on it.
+// Missing hotels, rate limiting and a cancelled sweep never fall back.
+func (s *AdminService) staleAvailability(ctx context.Context, booking *hotelclient.Booking, err error) (*hotelclient.HotelInfo, bool) {
+	var rateLimitErr *hotelclient.RateLimitError
+	if ctx.Err() != nil || errors.Is(err, hotelclient.ErrNotFound) || errors.As(err, &rateLimitErr) {
+		return nil, false
+	}
+
+	hotel, age, ok := s.decisions.Stale(booking)
+	if !ok {
+		return nil, false
+	}
+
+	log.Printf("Availability check for hotel %s failed (%v), deciding booking %s on availability from %s ago", booking.HotelID, err, booking.BookingID, age.Round(time.Second))
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetAttributes(
+		attribute.Bool("stale_availability", true),
+		attribute.Float64("stale_availability_age_seconds", age.Seconds()),
+	)
+	return hotel, true
+}
+
+// generateConfirmationNumber returns a new confirmation number for a booking
+// approved at tier, starting with the tier's prefix from
+// CONFIRMATION_TIER_PREFIXES or else CONFIRMATION_PREFIX. Random hex digits
+// fill the rest, so every confirmation number has the same length.
+func (s *AdminService) generateConfirmationNumber(tier string) string {
+	prefix, ok := s.cfg.ConfirmationTierPrefixes[tier]
+	if !ok {
+		prefix = s.cfg.ConfirmationPrefix
+	}
+	digits := fmt.Sprintf("%016X", rand.Uint64())
+	return prefix + digits[:confirmationNumberLength-len(prefix)]
+}
+
+// approveBooking confirms a pending booking and returns the resulting
+// approval. hotel holds the availability observed when the decision was made
+// and may be nil when it wasn't checked; note is the operator's optional note.
+func (s *AdminService) approveBooking(ctx context.Context, booking *hotelclient.Booking, hotel *hotelclient.HotelInfo, note string, autoApproval bool) (ApprovalEvent, error) {
 	if booking.Status != "pending" {
-		return fmt.Errorf("booking is already %s", booking.Status)
+		return ApprovalEvent{}, &notPendingError{status: booking.Status}
+	}
+
+	availability := ""
+	if hotel != nil {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int("available_rooms", hotel.AvailableRooms))
+		availability = fmt.Sprintf(" (%d rooms available)", hotel.AvailableRooms)
+	}
+
+	for _, hook := range s.hooks {
+		if err := hook.BeforeApprove(ctx, booking); err != nil {
+			return ApprovalEvent{}, fmt.Errorf("approval blocked by hook: %w", err)
+		}
 	}
 
 	// Evaluate approval rules using Flipt
-	tier, err := s.evaluateApprovalRules(ctx, booking)
+	tier, err := s.approvalTier(ctx, booking, autoApproval)
 	if err != nil {
-		return err
+		return ApprovalEvent{}, err
 	}
 
-	confirmationNumber := fmt.Sprintf("CNF-%000000X", rand.Int64N(time.Now().Unix()))
-	err = s.hotelClient.UpdateBooking(ctx, booking.BookingID, hotelclient.BookingUpdateRequest{
-		Status:             "confirmed",
-		ConfirmationNumber: &confirmationNumber,
+	// The tier decides how long the hotel holds the confirmation
+	expiresAt := timeNow().Add(s.tierSLA(tier)).UTC()
+
+	confirmationNumber := s.generateConfirmationNumber(tier)
+	err = s.updateBooking(ctx, booking.BookingID, hotelclient.BookingUpdateRequest{
+		Status:                "confirmed",
+		ConfirmationNumber:    &confirmationNumber,
+		ConfirmationExpiresAt: &expiresAt,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to approve booking: %w", err)
+		return ApprovalEvent{}, fmt.Errorf("failed to approve booking: %w", err)
 	}
+	s.decisions.Invalidate(booking.BookingID)
+	s.tierStats.Record(tier)
 
-	s.approvalCounter.Add(ctx, 1, metric.WithAttributes(
-		attribute.String("booking_id", booking.BookingID),
-		attribute.String("hotel_id", booking.HotelID),
-		attribute.String("status", "approved"),
-		attribute.String("tier", tier),
-		attribute.Bool("auto_approval", autoApproval),
-	))
+	metricsFromContext(ctx).Add(ctx, s.approvalCounter, 1,
+		append(s.bookingMetricAttrs(booking.BookingID),
+			attribute.String("hotel_id", booking.HotelID),
+			attribute.String("status", "approved"),
+			attribute.String("tier", tier),
+			attribute.Bool("auto_approval", autoApproval),
+		)...,
+	)
+
+	entry := AuditEntry{
+		Timestamp:          timeNow(),
+		BookingID:          booking.BookingID,
+		HotelID:            booking.HotelID,
+		Action:             "approved",
+		AutoApproval:       autoApproval,
+		Tier:               tier,
+		ConfirmationNumber: confirmationNumber,
+		Note:               note,
+	}
+	if hotel != nil {
+		entry.AvailableRooms = &hotel.AvailableRooms
+	}
+	s.auditLog.Record(entry)
+	bookingEvent := BookingEvent{
+		BookingID:    booking.BookingID,
+		HotelID:      booking.HotelID,
+		Status:       "confirmed",
+		Tier:         tier,
+		AutoApproval: autoApproval,
+		Timestamp:    entry.Timestamp,
+	}
+	s.events.Publish(bookingEvent)
+	s.notifier.Notify(ctx, bookingEvent)
+
+	event := ApprovalEvent{
+		Booking:               booking,
+		Tier:                  tier,
+		ConfirmationNumber:    confirmationNumber,
+		ConfirmationExpiresAt: expiresAt,
+		AutoApproval:          autoApproval,
+		Note:                  note,
+	}
+	for _, hook := range s.hooks {
+		hook.AfterApprove(ctx, event)
+	}
 
 	approvalType := "manually approved"
 	if autoApproval {
 		approvalType = "auto-approved"
 	}
-	log.Printf("Booking %s %s with confirmation %s", booking.BookingID, approvalType, confirmationNumber)
-	return nil
+	log.Printf("Booking %s %s with confirmation %s%s", booking.BookingID, approvalType, confirmationNumber, availability)
+	return event, nil
+}
+
+// tierSLA returns how long a confirmation is held for an approval tier,
+// falling back to the default for tiers without a configured SLA.
+func (s *AdminService) tierSLA(tier string) time.Duration {
+	if sla, ok := s.cfg.ApprovalTierSLAs[tier]; ok {
+		return sla
+	}
+	return s.cfg.ApprovalDefaultSLA
 }
 
-func (s *AdminService) rejectBooking(ctx context.Context, booking *hotelclient.Booking, reason string, autoApproval bool) error {
+// rejectBooking rejects a pending booking. reasonKey is a low-cardinality
+// key recorded in metrics; reason is the human-readable text.
+func (s *AdminService) rejectBooking(ctx context.Context, booking *hotelclient.Booking, reasonKey, reason string, autoApproval bool) error {
 	if booking.Status != "pending" {
-		return fmt.Errorf("booking is already %s", booking.Status)
+		return &notPendingError{status: booking.Status}
 	}
 
-	err := s.hotelClient.UpdateBooking(ctx, booking.BookingID, hotelclient.BookingUpdateRequest{
+	err := s.updateBooking(ctx, booking.BookingID, hotelclient.BookingUpdateRequest{
 		Status: "rejected",
 	})
 	if err != nil {
 		return fmt.Errorf("failed to reject booking: %w", err)
 	}
+	s.decisions.Invalidate(booking.BookingID)
 
-	s.approvalCounter.Add(ctx, 1, metric.WithAttributes(
-		attribute.String("booking_id", booking.BookingID),
-		attribute.String("hotel_id", booking.HotelID),
-		attribute.String("status", "rejected"),
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("reason_code", reasonKey),
 		attribute.String("reason", reason),
-		attribute.Bool("auto_approval", autoApproval),
-	))
+	)
+	metricsFromContext(ctx).Add(ctx, s.approvalCounter, 1,
+		append(s.bookingMetricAttrs(booking.BookingID),
+			attribute.String("hotel_id", booking.HotelID),
+			attribute.String("status", "rejected"),
+			attribute.String("reason", sanitizeMetricValue(reasonKey, slices.Concat(metricReasons, s.cfg.RejectReasonCodes))),
+			attribute.Bool("auto_approval", autoApproval),
+		)...,
+	)
+
+	s.auditLog.Record(AuditEntry{
+		Timestamp:    timeNow(),
+		BookingID:    booking.BookingID,
+		HotelID:      booking.HotelID,
+		Action:       "rejected",
+		AutoApproval: autoApproval,
+		ReasonCode:   reasonKey,
+		Reason:       reason,
+	})
+	bookingEvent := BookingEvent{
+		BookingID:    booking.BookingID,
+		HotelID:      booking.HotelID,
+		Status:       "rejected",
+		Reason:       reason,
+		AutoApproval: autoApproval,
+		Timestamp:    timeNow(),
+	}
+	s.events.Publish(bookingEvent)
+	s.notifier.Notify(ctx, bookingEvent)
 
 	rejectionType := "manually rejected"
 	if autoApproval {
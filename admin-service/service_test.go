@@ -0,0 +1,763 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestConfirmationNumberPrefixPerTier(t *testing.T) {
+	svc := &AdminService{cfg: Config{
+		ConfirmationPrefix:       "CNF-",
+		ConfirmationTierPrefixes: map[string]string{"vip": "VIP-", "premium": "P"},
+	}}
+
+	for tier, prefix := range map[string]string{
+		"vip":      "VIP-",
+		"premium":  "P",
+		"standard": "CNF-",
+		"":         "CNF-",
+	} {
+		number := svc.generateConfirmationNumber(tier)
+		if !strings.HasPrefix(number, prefix) {
+			t.Errorf("tier %q: confirmation number %s doesn't start with %s", tier, number, prefix)
+		}
+		if len(number) != confirmationNumberLength {
+			t.Errorf("tier %q: confirmation number %s has %d characters, want %d", tier, number, len(number), confirmationNumberLength)
+		}
+	}
+}
+
+func newManualApprovalService(t *testing.T) (*AdminService, *fakeHotelService) {
+	t.Helper()
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	evaluator.setVariant("approval-tier", "standard")
+	return newTestService(t, evaluator, hotel, nil), hotel
+}
+
+func TestManualApprovalRecordsNote(t *testing.T) {
+	svc, hotel := newManualApprovalService(t)
+
+	rec := serve(svc, http.MethodPost, "/api/bookings/b1/approve", `{"note": "called the guest"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body)
+	}
+	var resp BookingDecisionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Note != "called the guest" {
+		t.Errorf("response note = %q", resp.Note)
+	}
+	entries := svc.auditLog.ForBooking("b1")
+	if len(entries) != 1 || entries[0].Note != "called the guest" {
+		t.Errorf("audit entries = %+v, want one with the note", entries)
+	}
+	if status := hotel.booking("b1").Status; status != "confirmed" {
+		t.Errorf("booking status = %s, want confirmed", status)
+	}
+}
+
+func TestManualApprovalWithoutBody(t *testing.T) {
+	svc, _ := newManualApprovalService(t)
+
+	rec := serve(svc, http.MethodPost, "/api/bookings/b1/approve", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body)
+	}
+	if entries := svc.auditLog.ForBooking("b1"); len(entries) != 1 || entries[0].Note != "" {
+		t.Errorf("audit entries = %+v, want one without a note", entries)
+	}
+}
+
+func TestManualApprovalRejectsOversizedBodies(t *testing.T) {
+	for name, body := range map[string]string{
+		"note too long": fmt.Sprintf(`{"note": %q}`, strings.Repeat("a", approvalNoteMaxLength+1)),
+		"body too big":  fmt.Sprintf(`{"note": "a", "padding": %q}`, strings.Repeat("a", maxApprovalBodyBytes)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, hotel := newManualApprovalService(t)
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/b1/approve", body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("approve = %d, want 400", rec.Code)
+			}
+			if status := hotel.booking("b1").Status; status != "pending" {
+				t.Errorf("booking status = %s, want pending", status)
+			}
+		})
+	}
+}
+
+func TestManualDecisionErrorStatuses(t *testing.T) {
+	for _, action := range []string{"approve", "reject"} {
+		t.Run(action+" with auto-approval enabled", func(t *testing.T) {
+			svc, _ := newManualApprovalService(t)
+			svc.evaluator.(*fakeEvaluator).setBoolean("auto-approval", true)
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/b1/"+action, `{"reason": "test"}`)
+			assertDecisionError(t, rec, http.StatusForbidden, DecisionAutoApprovalActive)
+		})
+		t.Run(action+" of a decided booking", func(t *testing.T) {
+			svc, hotel := newManualApprovalService(t)
+			hotel.setStatus("b1", "confirmed")
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/b1/"+action, `{"reason": "test"}`)
+			assertDecisionError(t, rec, http.StatusConflict, DecisionAlreadyTerminal)
+		})
+	}
+}
+
+func assertDecisionError(t *testing.T, rec *httptest.ResponseRecorder, status int, outcome DecisionOutcome) {
+	t.Helper()
+	if rec.Code != status {
+		t.Errorf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Outcome != outcome {
+		t.Errorf("outcome = %s, want %s", resp.Outcome, outcome)
+	}
+}
+
+func TestReadinessChecksHotelServiceHealth(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		health int
+		want   int
+	}{
+		{"healthy", http.StatusOK, http.StatusOK},
+		{"unhealthy", http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hotel := newFakeHotelService(t)
+			hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+				if r.URL.Path != "/status" {
+					return false
+				}
+				w.WriteHeader(tc.health)
+				return true
+			}
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", false)
+			svc := NewAdminService(evaluator, hotel.client(hotelclient.WithHealthPath("/status")), loadConfig(), nil)
+
+			rec := serve(svc, http.MethodGet, "/ready", "")
+			if rec.Code != tc.want {
+				t.Errorf("ready = %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+			if pinged := hotel.requested(http.MethodGet); len(pinged) != 1 || pinged[0] != "/status" {
+				t.Errorf("hotel service requests = %v, want the health path", pinged)
+			}
+		})
+	}
+}
+
+func TestEvaluationContextMergesAmbientKeys(t *testing.T) {
+	t.Setenv("REGION", "eu-west-1")
+	t.Setenv("CLUSTER", "")
+	if got := loadEvaluationContext(); !maps.Equal(got, map[string]string{"region": "eu-west-1"}) {
+		t.Fatalf("loadEvaluationContext() = %v, want only the region", got)
+	}
+
+	svc := newTestService(t, newFakeEvaluator(), newFakeHotelService(t), func(cfg *Config) {
+		cfg.EvaluationContext = map[string]string{
+			"region":   "eu-west-1",
+			"cluster":  "blue",
+			"hotel_id": "ambient",
+		}
+	})
+	booking := pendingBooking("booking_1", "hotel_1")
+	req := svc.manualReviewRequest(context.Background(), &booking)
+
+	if got := req.Context["region"]; got != "eu-west-1" {
+		t.Errorf("region = %q, want the ambient eu-west-1", got)
+	}
+	if got := req.Context["cluster"]; got != "blue" {
+		t.Errorf("cluster = %q, want the ambient blue", got)
+	}
+	if got := req.Context["hotel_id"]; got != "hotel_1" {
+		t.Errorf("hotel_id = %q, want the booking's hotel_1 to override the ambient key", got)
+	}
+	if got := svc.cfg.EvaluationContext["hotel_id"]; got != "ambient" {
+		t.Errorf("ambient hotel_id = %q after evaluation, want it left unchanged", got)
+	}
+}
+
+func TestBookingCacheControlByStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   string
+	}{
+		{"pending", "no-store"},
+		{"confirmed", "max-age=60"},
+		{"rejected", "max-age=60"},
+	} {
+		booking := pendingBooking("booking_1", "hotel_1")
+		booking.Status = tc.status
+		svc := newTestService(t, newFakeEvaluator(), newFakeHotelService(t, booking), nil)
+
+		rec := serve(svc, http.MethodGet, "/api/bookings/booking_1", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: get booking = %d: %s", tc.status, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != tc.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestUnknownApprovalTierFallsBackToDefault(t *testing.T) {
+	reader := recordMetrics(t)
+
+	for _, tc := range []struct {
+		variant string
+		want    string
+	}{
+		{"standard", "standard"},
+		{"vip", "vip"},
+		{"platinum", "premium"},
+		{"", "premium"},
+	} {
+		evaluator := newFakeEvaluator()
+		evaluator.setVariant("approval-tier", tc.variant)
+		svc := newTestService(t, evaluator, newFakeHotelService(t), func(cfg *Config) {
+			cfg.ApprovalKnownTiers = []string{"standard", "premium", "vip"}
+			cfg.ApprovalDefaultTier = "premium"
+		})
+		booking := pendingBooking("b1", "hotel_1")
+
+		tier, err := svc.evaluateApprovalRules(context.Background(), &booking)
+		if err != nil {
+			t.Fatalf("variant %q: %v", tc.variant, err)
+		}
+		if tier != tc.want {
+			t.Errorf("variant %q: tier = %q, want %q", tc.variant, tier, tc.want)
+		}
+	}
+
+	unknown := map[string]int64{}
+	for _, dp := range collectMetric(t, reader, "admin_unknown_tier_total").Data.(metricdata.Sum[int64]).DataPoints {
+		variant, _ := dp.Attributes.Value("variant")
+		unknown[variant.AsString()] += dp.Value
+	}
+	if want := map[string]int64{"platinum": 1, "": 1}; !maps.Equal(unknown, want) {
+		t.Errorf("admin_unknown_tier_total = %v, want %v", unknown, want)
+	}
+}
+
+func TestBookingStatusFilterValidation(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   int
+	}{
+		{"", http.StatusOK},
+		{"pending", http.StatusOK},
+		{"confirmed", http.StatusOK},
+		{"rejected", http.StatusOK},
+		{"cancelled", http.StatusOK},
+		{"confirmd", http.StatusBadRequest},
+		{"PENDING", http.StatusBadRequest},
+	} {
+		for _, path := range []string{"/api/bookings", "/api/bookings/export"} {
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+			svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+
+			target := path
+			if tc.status != "" {
+				target += "?status=" + tc.status
+			}
+			rec := serve(svc, http.MethodGet, target, "")
+			if rec.Code != tc.want {
+				t.Errorf("GET %s = %d, want %d: %s", target, rec.Code, tc.want, rec.Body)
+			}
+			if tc.want != http.StatusBadRequest {
+				continue
+			}
+			if !strings.Contains(rec.Body.String(), "pending, confirmed, rejected, cancelled") {
+				t.Errorf("GET %s error doesn't list the valid statuses: %s", target, rec.Body)
+			}
+			if fetched := hotel.requested(http.MethodGet); len(fetched) > 0 {
+				t.Errorf("GET %s forwarded an invalid status upstream: %v", target, fetched)
+			}
+		}
+	}
+}
+
+func TestApprovalTierEntityIDFallbacks(t *testing.T) {
+	withoutEmail := pendingBooking("b2", "hotel_1")
+	withoutEmail.GuestEmail = ""
+
+	for _, tc := range []struct {
+		name    string
+		booking hotelclient.Booking
+		entity  string
+		warned  bool
+	}{
+		{"guest email", pendingBooking("b1", "hotel_1"), "guest@example.com", false},
+		{"booking without email", withoutEmail, "b2", true},
+		{"flag preview", hotelclient.Booking{}, anonymousEntityID, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := captureLog(t)
+			evaluator := newFakeEvaluator()
+			evaluator.setVariant("approval-tier", "standard")
+			svc := newTestService(t, evaluator, newFakeHotelService(t), nil)
+
+			if _, err := svc.evaluateApprovalRules(context.Background(), &tc.booking); err != nil {
+				t.Fatal(err)
+			}
+			if reqs := evaluator.evaluated("approval-tier"); len(reqs) != 1 || reqs[0].EntityID != tc.entity {
+				t.Errorf("approval-tier evaluations = %+v, want entity %q", reqs, tc.entity)
+			}
+			if warned := strings.Contains(logs.String(), "has no guest email"); warned != tc.warned {
+				t.Errorf("warned = %t, want %t: %s", warned, tc.warned, logs)
+			}
+		})
+	}
+}
+
+func TestApprovalTierOverridesSkipFlipt(t *testing.T) {
+	for _, tc := range []struct {
+		email     string
+		tier      string
+		evaluated bool
+	}{
+		{"vip@example.com", "vip", false},
+		{"VIP@Example.com", "vip", false},
+		{"typo@example.com", "standard", true},
+		{"guest@example.com", "standard", true},
+	} {
+		evaluator := newFakeEvaluator()
+		evaluator.setVariant("approval-tier", "standard")
+		svc := newTestService(t, evaluator, newFakeHotelService(t), func(cfg *Config) {
+			cfg.ApprovalKnownTiers = []string{"standard", "premium", "vip"}
+			cfg.ApprovalTierOverrides = map[string]string{
+				"vip@example.com":  "vip",
+				"typo@example.com": "platinum",
+			}
+		})
+		booking := pendingBooking("b1", "hotel_1")
+		booking.GuestEmail = tc.email
+
+		tier, err := svc.evaluateApprovalRules(context.Background(), &booking)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.email, err)
+		}
+		if tier != tc.tier {
+			t.Errorf("%s: tier = %q, want %q", tc.email, tier, tc.tier)
+		}
+		if evaluated := len(evaluator.evaluated("approval-tier")) > 0; evaluated != tc.evaluated {
+			t.Errorf("%s: approval-tier evaluated = %t, want %t", tc.email, evaluated, tc.evaluated)
+		}
+	}
+
+	if overrides := loadConfig().ApprovalTierOverrides; len(overrides) != 0 {
+		t.Errorf("default ApprovalTierOverrides = %v, want none", overrides)
+	}
+}
+
+func TestAvailabilityBufferBoundary(t *testing.T) {
+	for _, tc := range []struct {
+		buffer, rooms int
+		outcome       string
+		status        string
+	}{
+		{0, 1, outcomeApproved, "confirmed"},
+		{0, 0, outcomeRejected, "rejected"},
+		{2, 3, outcomeApproved, "confirmed"},
+		{2, 2, outcomeSkipped, "pending"},
+		{2, 1, outcomeSkipped, "pending"},
+		{2, 0, outcomeRejected, "rejected"},
+	} {
+		recorder := recordSpans(t)
+		hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+		hotel.availableRooms["hotel_1"] = tc.rooms
+		evaluator := newFakeEvaluator()
+		evaluator.setBoolean("require-manual-review", false)
+		evaluator.setVariant("approval-tier", "standard")
+		svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.MinAvailabilityBuffer = tc.buffer })
+		booking := hotel.booking("b1")
+
+		outcome, err := svc.processBooking(context.Background(), &booking)
+		if err != nil {
+			t.Fatalf("buffer %d, %d rooms: %v", tc.buffer, tc.rooms, err)
+		}
+		if outcome != tc.outcome {
+			t.Errorf("buffer %d, %d rooms: outcome = %s, want %s", tc.buffer, tc.rooms, outcome, tc.outcome)
+		}
+		if status := hotel.booking("b1").Status; status != tc.status {
+			t.Errorf("buffer %d, %d rooms: status = %s, want %s", tc.buffer, tc.rooms, status, tc.status)
+		}
+
+		spans := recorder.Ended()
+		i := slices.IndexFunc(spans, func(span sdktrace.ReadOnlySpan) bool { return span.Name() == "process_booking" })
+		if i < 0 {
+			t.Fatal("no process_booking span recorded")
+		}
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		if got, _ := attrs.Value("availability_buffer"); got.AsInt64() != int64(tc.buffer) {
+			t.Errorf("buffer %d, %d rooms: availability_buffer = %d", tc.buffer, tc.rooms, got.AsInt64())
+		}
+		if got, _ := attrs.Value("above_availability_buffer"); got.AsBool() != (tc.rooms > tc.buffer) {
+			t.Errorf("buffer %d, %d rooms: above_availability_buffer = %t", tc.buffer, tc.rooms, got.AsBool())
+		}
+	}
+}
+
+func TestAdminFeatureTargetsAuthenticatedUser(t *testing.T) {
+	keys := map[string]Principal{
+		"ops-key":     {User: "ops", Role: RoleApprover},
+		"support-key": {User: "support", Role: RoleViewer},
+	}
+
+	for _, tc := range []struct {
+		name, key    string
+		auth         bool
+		entity, role string
+	}{
+		{name: "approver", key: "ops-key", auth: true, entity: "ops", role: "approver"},
+		{name: "viewer", key: "support-key", auth: true, entity: "support", role: "viewer"},
+		{name: "unauthenticated", entity: anonymousEntityID, role: anonymousEntityID},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", true)
+			// Rolled out to authenticated admins only
+			evaluator.setBoolean("new-approval-ui", tc.auth)
+			svc := newTestService(t, evaluator, newFakeHotelService(t), func(cfg *Config) {
+				cfg.AdminFeatureFlagKey = "new-approval-ui"
+			})
+			handler := api.HandlerFromMux(svc, http.NewServeMux())
+			if tc.auth {
+				handler = authMiddleware(keys, "")(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/flags", nil)
+			if tc.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET /api/flags = %d: %s", rec.Code, rec.Body)
+			}
+
+			reqs := evaluator.evaluated("new-approval-ui")
+			if len(reqs) != 1 {
+				t.Fatalf("new-approval-ui evaluated %d times, want once", len(reqs))
+			}
+			if reqs[0].EntityID != tc.entity || reqs[0].Context["role"] != tc.role {
+				t.Errorf("evaluated with entity %q and role %q, want %q and %q", reqs[0].EntityID, reqs[0].Context["role"], tc.entity, tc.role)
+			}
+
+			var resp FlagStatusResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			want := AdminFeatureStatus{FlagKey: "new-approval-ui", EntityID: tc.entity, Enabled: tc.auth}
+			if resp.AdminFeature != want {
+				t.Errorf("admin_feature = %+v, want %+v", resp.AdminFeature, want)
+			}
+		})
+	}
+}
+
+func TestBookingListMetricShapes(t *testing.T) {
+	reader := recordMetrics(t)
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"), pendingBooking("b2", "hotel_1"), pendingBooking("b3", "hotel_2"))
+	svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+
+	for range 2 {
+		if rec := serve(svc, http.MethodGet, "/api/bookings?status=pending", ""); rec.Code != http.StatusOK {
+			t.Fatalf("list bookings = %d: %s", rec.Code, rec.Body)
+		}
+	}
+
+	status := attribute.NewSet(attribute.String("status", "pending"))
+	views := collectMetric(t, reader, "admin_booking_views_total").Data.(metricdata.Sum[int64]).DataPoints
+	if len(views) != 1 || !views[0].Attributes.Equals(&status) || views[0].Value != 2 {
+		t.Errorf("admin_booking_views_total = %+v, want 2 views labeled only with the status", views)
+	}
+	returned := collectMetric(t, reader, "admin_bookings_returned").Data.(metricdata.Histogram[int64]).DataPoints
+	if len(returned) != 1 || !returned[0].Attributes.Equals(&status) || returned[0].Count != 2 || returned[0].Sum != 6 {
+		t.Errorf("admin_bookings_returned = %+v, want two lists of 3 bookings labeled with the status", returned)
+	}
+}
+
+func TestManualApprovalAgeLimit(t *testing.T) {
+	created := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		age   time.Duration
+		query string
+		want  int
+	}{
+		{"within the limit", 23 * time.Hour, "", http.StatusOK},
+		{"at the limit", 24 * time.Hour, "", http.StatusOK},
+		{"beyond the limit", 48 * time.Hour, "", http.StatusUnprocessableEntity},
+		{"beyond the limit with override", 48 * time.Hour, "?override_max_age=true", http.StatusOK},
+		{"beyond the limit without override", 48 * time.Hour, "?override_max_age=false", http.StatusUnprocessableEntity},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setClock(t, created.Add(tc.age))
+			recorder := recordSpans(t)
+			svc, hotel := newManualApprovalService(t)
+			svc.cfg.MaxManualApproveAge = 24 * time.Hour
+
+			rec := serve(svc, http.MethodPost, "/api/bookings/b1/approve"+tc.query, "")
+			if rec.Code != tc.want {
+				t.Fatalf("approve = %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+			wantStatus := "confirmed"
+			if tc.want != http.StatusOK {
+				wantStatus = "pending"
+			}
+			if status := hotel.booking("b1").Status; status != wantStatus {
+				t.Errorf("booking status = %s, want %s", status, wantStatus)
+			}
+
+			var age attribute.Value
+			for _, span := range recorder.Ended() {
+				attrs := attribute.NewSet(span.Attributes()...)
+				if v, ok := attrs.Value("booking_age_seconds"); ok {
+					age = v
+				}
+			}
+			if age.AsFloat64() != tc.age.Seconds() {
+				t.Errorf("booking_age_seconds = %v, want %v", age.AsFloat64(), tc.age.Seconds())
+			}
+		})
+	}
+}
+
+func TestHotelMinimumGuests(t *testing.T) {
+	t.Setenv("HOTEL_MIN_GUESTS", "hotel_1:2, hotel_2:4, hotel_3:many")
+	if got, want := loadConfig().HotelMinGuests, map[string]int{"hotel_1": 2, "hotel_2": 4}; !maps.Equal(got, want) {
+		t.Errorf("HotelMinGuests = %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		hotelID string
+		guests  int
+		outcome string
+		minimum int64
+	}{
+		{"hotel_1", 1, outcomeRejected, 2},
+		{"hotel_1", 2, outcomeApproved, 2},
+		{"hotel_2", 3, outcomeRejected, 4},
+		{"hotel_9", 1, outcomeApproved, 0},
+	} {
+		recorder := recordSpans(t)
+		booking := pendingBooking("b1", tc.hotelID)
+		booking.Guests = tc.guests
+		hotel := newFakeHotelService(t, booking)
+		evaluator := newFakeEvaluator()
+		evaluator.setBoolean("require-manual-review", false)
+		evaluator.setVariant("approval-tier", "standard")
+		svc := newTestService(t, evaluator, hotel, nil)
+
+		outcome, err := svc.processBooking(context.Background(), &booking)
+		if err != nil {
+			t.Fatalf("%s with %d guests: %v", tc.hotelID, tc.guests, err)
+		}
+		if outcome != tc.outcome {
+			t.Errorf("%s with %d guests: outcome = %s, want %s", tc.hotelID, tc.guests, outcome, tc.outcome)
+		}
+
+		spans := recorder.Ended()
+		i := slices.IndexFunc(spans, func(span sdktrace.ReadOnlySpan) bool { return span.Name() == "process_booking" })
+		if i < 0 {
+			t.Fatal("no process_booking span recorded")
+		}
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		minimum, hasMinimum := attrs.Value("min_guests")
+		guests, _ := attrs.Value("guests")
+		if hasMinimum != (tc.minimum > 0) || minimum.AsInt64() != tc.minimum {
+			t.Errorf("%s with %d guests: min_guests = %v, want %d", tc.hotelID, tc.guests, minimum.AsInt64(), tc.minimum)
+		}
+		if hasMinimum && guests.AsInt64() != int64(tc.guests) {
+			t.Errorf("%s with %d guests: guests attribute = %d", tc.hotelID, tc.guests, guests.AsInt64())
+		}
+	}
+}
+
+func TestFlagStatusApprovalTierPreview(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		query   string
+		want    ApprovalTierStatus
+		context map[string]string
+	}{
+		{
+			name: "no preview booking",
+			want: ApprovalTierStatus{FlagKey: "approval-tier"},
+		},
+		{
+			name:    "preview booking",
+			query:   "?hotel_id=hotel_7&total_price=650",
+			want:    ApprovalTierStatus{FlagKey: "approval-tier", Evaluated: true, Variant: "vip"},
+			context: map[string]string{"hotel_id": "hotel_7", "total_price": "650.00"},
+		},
+		{
+			name:    "preview price only",
+			query:   "?total_price=80",
+			want:    ApprovalTierStatus{FlagKey: "approval-tier", Evaluated: true, Variant: "vip"},
+			context: map[string]string{"hotel_id": "", "total_price": "80.00"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", true)
+			evaluator.setVariant("approval-tier", "vip")
+			svc := newTestService(t, evaluator, newFakeHotelService(t), nil)
+
+			rec := serve(svc, http.MethodGet, "/api/flags"+tc.query, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET /api/flags%s = %d: %s", tc.query, rec.Code, rec.Body)
+			}
+			var resp FlagStatusResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ApprovalTier != tc.want {
+				t.Errorf("approval_tier = %+v, want %+v", resp.ApprovalTier, tc.want)
+			}
+
+			reqs := evaluator.evaluated("approval-tier")
+			if tc.context == nil {
+				if len(reqs) > 0 {
+					t.Errorf("approval-tier evaluated %d times without a preview booking", len(reqs))
+				}
+				return
+			}
+			if len(reqs) != 1 {
+				t.Fatalf("approval-tier evaluated %d times, want once", len(reqs))
+			}
+			for key, want := range tc.context {
+				if got := reqs[0].Context[key]; got != want {
+					t.Errorf("evaluation context %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApprovalTierFallbackChain(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		primary   string
+		chain     []string
+		variants  map[string]string
+		want      string
+		evaluated []string
+		fallbacks map[string]int64
+	}{
+		{
+			name:      "primary match skips the chain",
+			primary:   "standard",
+			chain:     []string{"tier-by-hotel", "tier-by-price"},
+			variants:  map[string]string{"tier-by-hotel": "vip", "tier-by-price": "vip"},
+			want:      "standard",
+			fallbacks: map[string]int64{},
+		},
+		{
+			name:      "first non-empty variant wins",
+			chain:     []string{"tier-by-hotel", "tier-by-price", "tier-by-region"},
+			variants:  map[string]string{"tier-by-hotel": "", "tier-by-price": "vip", "tier-by-region": "standard"},
+			want:      "vip",
+			evaluated: []string{"tier-by-hotel", "tier-by-price"},
+			fallbacks: map[string]int64{"tier-by-price": 1},
+		},
+		{
+			name:      "failing flag is skipped",
+			chain:     []string{"tier-missing", "tier-by-price"},
+			variants:  map[string]string{"tier-by-price": "vip"},
+			want:      "vip",
+			evaluated: []string{"tier-missing", "tier-by-price"},
+			fallbacks: map[string]int64{"tier-by-price": 1},
+		},
+		{
+			name:      "exhausted chain uses the default tier",
+			chain:     []string{"tier-by-hotel", "tier-by-price"},
+			variants:  map[string]string{"tier-by-hotel": "", "tier-by-price": ""},
+			want:      "premium",
+			evaluated: []string{"tier-by-hotel", "tier-by-price"},
+			fallbacks: map[string]int64{"none": 1},
+		},
+		{
+			name:      "no chain by default",
+			want:      "premium",
+			fallbacks: map[string]int64{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := recordMetrics(t)
+			evaluator := newFakeEvaluator()
+			evaluator.setVariant("approval-tier", tc.primary)
+			for flag, variant := range tc.variants {
+				evaluator.setVariant(flag, variant)
+			}
+			svc := newTestService(t, evaluator, newFakeHotelService(t), func(cfg *Config) {
+				cfg.ApprovalKnownTiers = []string{"standard", "premium", "vip"}
+				cfg.ApprovalDefaultTier = "premium"
+				cfg.ApprovalTierFallbackFlags = tc.chain
+			})
+			booking := pendingBooking("b1", "hotel_1")
+
+			tier, err := svc.evaluateApprovalRules(context.Background(), &booking)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tier != tc.want {
+				t.Errorf("tier = %q, want %q", tier, tc.want)
+			}
+
+			primary := evaluator.evaluated("approval-tier")
+			if len(primary) != 1 {
+				t.Fatalf("approval-tier evaluated %d times, want once", len(primary))
+			}
+			var evaluated []string
+			for _, flag := range tc.chain {
+				for _, req := range evaluator.evaluated(flag) {
+					evaluated = append(evaluated, flag)
+					if req.EntityID != primary[0].EntityID || !maps.Equal(req.Context, primary[0].Context) {
+						t.Errorf("%s evaluated as %s with %v, want the approval-tier entity %s and context %v",
+							flag, req.EntityID, req.Context, primary[0].EntityID, primary[0].Context)
+					}
+				}
+			}
+			if !slices.Equal(evaluated, tc.evaluated) {
+				t.Errorf("fallback flags evaluated = %v, want %v", evaluated, tc.evaluated)
+			}
+
+			fallbacks := map[string]int64{}
+			if len(tc.fallbacks) > 0 {
+				for _, dp := range collectMetric(t, reader, "admin_approval_tier_fallbacks_total").Data.(metricdata.Sum[int64]).DataPoints {
+					flag, _ := dp.Attributes.Value("flag")
+					fallbacks[flag.AsString()] += dp.Value
+				}
+			}
+			if !maps.Equal(fallbacks, tc.fallbacks) {
+				t.Errorf("admin_approval_tier_fallbacks_total = %v, want %v", fallbacks, tc.fallbacks)
+			}
+		})
+	}
+}
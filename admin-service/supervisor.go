@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Component is a background part of the service whose lifecycle is managed
+// by a Supervisor
+type Component interface {
+	// Name identifies the component in logs and errors
+	Name() string
+	// Start launches the component and returns once it is running. Long
+	// running work belongs on a goroutine owned by the component.
+	Start(ctx context.Context) error
+	// Stop shuts the component down, returning once it has finished or ctx
+	// is done
+	Stop(ctx context.Context) error
+}
+
+// Supervisor starts registered components in registration order and stops
+// them in reverse order, so a component can rely on everything registered
+// before it for its whole lifetime.
+type Supervisor struct {
+	stopTimeout time.Duration
+
+	mu      sync.Mutex
+	started []Component
+	pending []Component
+}
+
+// NewSupervisor creates a supervisor that gives each component up to
+// stopTimeout to stop. A zero timeout leaves components bounded only by the
+// context passed to Stop.
+func NewSupervisor(stopTimeout time.Duration) *Supervisor {
+	return &Supervisor{stopTimeout: stopTimeout}
+}
+
+// Register adds a component to be started by the next call to Start
+func (s *Supervisor) Register(c Component) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, c)
+}
+
+// Start starts the registered components in order. If one fails, the
+// components already started are stopped again before the error is returned.
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	for _, c := range pending {
+		if err := c.Start(ctx); err != nil {
+			stopErr := s.Stop(context.WithoutCancel(ctx))
+			return errors.Join(fmt.Errorf("starting %s: %w", c.Name(), err), stopErr)
+		}
+
+		s.mu.Lock()
+		s.started = append(s.started, c)
+		s.mu.Unlock()
+		log.Printf("Started %s", c.Name())
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Every component is
+// asked to stop even if an earlier one fails or times out; their errors are
+// joined.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	started := s.started
+	s.started = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+
+		stopCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.stopTimeout > 0 {
+			stopCtx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+		}
+		err := c.Stop(stopCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Failed to stop %s: %v", c.Name(), err)
+			errs = append(errs, fmt.Errorf("stopping %s: %w", c.Name(), err))
+			continue
+		}
+		log.Printf("Stopped %s", c.Name())
+	}
+	return errors.Join(errs...)
+}
+
+// loopComponent runs a blocking function on its own goroutine until stopped
+type loopComponent struct {
+	name   string
+	run    func(ctx context.Context)
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewLoopComponent adapts a function that runs until its context is done,
+// such as a worker's poll loop, into a Component. The loop's context is
+// cancelled by Stop rather than by the context passed to Start, so the
+// Supervisor decides when it ends.
+func NewLoopComponent(name string, run func(ctx context.Context)) Component {
+	return &loopComponent{name: name, run: run}
+}
+
+func (c *loopComponent) Name() string {
+	return c.name
+}
+
+func (c *loopComponent) Start(ctx context.Context) error {
+	ctx, c.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		c.run(ctx)
+	}()
+	return nil
+}
+
+func (c *loopComponent) Stop(ctx context.Context) error {
+	c.cancel()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordingComponent appends its lifecycle events to a shared log
+type recordingComponent struct {
+	name     string
+	events   *[]string
+	startErr error
+	stopErr  error
+	block    bool
+}
+
+func (c *recordingComponent) Name() string {
+	return c.name
+}
+
+func (c *recordingComponent) Start(ctx context.Context) error {
+	*c.events = append(*c.events, "start "+c.name)
+	return c.startErr
+}
+
+func (c *recordingComponent) Stop(ctx context.Context) error {
+	*c.events = append(*c.events, "stop "+c.name)
+	if c.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return c.stopErr
+}
+
+func TestSupervisorStopsInReverseOrder(t *testing.T) {
+	var events []string
+	s := NewSupervisor(0)
+	for _, name := range []string{"a", "b", "c"} {
+		s.Register(&recordingComponent{name: name, events: &events})
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"start a", "start b", "start c", "stop c", "stop b", "stop a"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestSupervisorStopsEveryComponentDespiteFailures(t *testing.T) {
+	var events []string
+	stopErr := errors.New("stop failed")
+	s := NewSupervisor(10 * time.Millisecond)
+	s.Register(&recordingComponent{name: "a", events: &events})
+	s.Register(&recordingComponent{name: "b", events: &events, block: true})
+	s.Register(&recordingComponent{name: "c", events: &events, stopErr: stopErr})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := s.Stop(context.Background())
+	if !errors.Is(err, stopErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() = %v, want both the failure and the timeout", err)
+	}
+
+	want := []string{"start a", "start b", "start c", "stop c", "stop b", "stop a"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestSupervisorStopsStartedComponentsWhenStartFails(t *testing.T) {
+	var events []string
+	s := NewSupervisor(0)
+	s.Register(&recordingComponent{name: "a", events: &events})
+	s.Register(&recordingComponent{name: "b", events: &events, startErr: errors.New("start failed")})
+	s.Register(&recordingComponent{name: "c", events: &events})
+
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("Start succeeded, want error")
+	}
+
+	want := []string{"start a", "start b", "stop a"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
@@ -3,18 +3,35 @@ package main
 import (
 	"context"
 	"log"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-func setupOTEL(ctx context.Context) func() {
+// Values for OTEL_DEBUG_STDOUT
+const (
+	debugStdoutOff  = "false"
+	debugStdoutOn   = "true"
+	debugStdoutOnly = "only"
+)
+
+// debugStdoutMetricInterval is how often metrics are printed in debug mode.
+// It is longer than the OTLP interval so the console isn't flooded.
+const debugStdoutMetricInterval = time.Minute
+
+func setupOTEL(ctx context.Context, cfg Config) func() {
 	// Create resource
 	res, err := resource.New(ctx,
 		resource.WithFromEnv(),
@@ -23,41 +40,80 @@ func setupOTEL(ctx context.Context) func() {
 		log.Printf("Failed to create resource: %v", err)
 	}
 
-	// Setup trace provider
-	traceExporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithInsecure(),
-	)
-	if err != nil {
-		log.Printf("Failed to create trace exporter: %v", err)
+	exportOTLP := cfg.OTELDebugStdout != debugStdoutOnly
+	exportStdout := cfg.OTELDebugStdout != debugStdoutOff
+
+	// Setup trace provider. The OTLP exporters take transport security
+	// from the environment: plain HTTP only for an http:// endpoint or with
+	// OTEL_EXPORTER_OTLP_INSECURE=true, TLS otherwise, so tokens in export
+	// headers aren't sent in the clear by default.
+	traceOpts := []trace.TracerProviderOption{trace.WithResource(res)}
+	if exportOTLP {
+		traceExporter, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithHeaders(otlpHeaders("TRACES", cfg.OTLPBearerToken)),
+			otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+				Enabled:         cfg.OTLPRetryEnabled,
+				InitialInterval: cfg.OTLPRetryInitialInterval,
+				MaxInterval:     cfg.OTLPRetryMaxInterval,
+				MaxElapsedTime:  cfg.OTLPRetryMaxElapsedTime,
+			}),
+		)
+		if err != nil {
+			log.Printf("Failed to create trace exporter: %v", err)
+		}
+		traceOpts = append(traceOpts, trace.WithBatcher(traceExporter))
+	}
+	if exportStdout {
+		// Batched so a request's spans are printed together rather than
+		// interleaved with log lines as each one ends
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			log.Printf("Failed to create stdout trace exporter: %v", err)
+		} else {
+			traceOpts = append(traceOpts, trace.WithBatcher(stdoutExporter))
+		}
 	}
 
-	tracerProvider := trace.NewTracerProvider(
-		trace.WithBatcher(traceExporter),
-		trace.WithResource(res),
-	)
+	tracerProvider := trace.NewTracerProvider(traceOpts...)
 	otel.SetTracerProvider(tracerProvider)
 
 	// Setup metric provider
-	metricExporter, err := otlpmetrichttp.New(ctx,
-		otlpmetrichttp.WithInsecure(),
-	)
-	if err != nil {
-		log.Printf("Failed to create metric exporter: %v", err)
+	metricOpts := []metric.Option{metric.WithResource(res)}
+	if exportOTLP {
+		metricExporter, err := otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithHeaders(otlpHeaders("METRICS", cfg.OTLPBearerToken)),
+			otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+				Enabled:         cfg.OTLPRetryEnabled,
+				InitialInterval: cfg.OTLPRetryInitialInterval,
+				MaxInterval:     cfg.OTLPRetryMaxInterval,
+				MaxElapsedTime:  cfg.OTLPRetryMaxElapsedTime,
+			}),
+		)
+		if err != nil {
+			log.Printf("Failed to create metric exporter: %v", err)
+		}
+		metricOpts = append(metricOpts, metric.WithReader(metric.NewPeriodicReader(metricExporter,
+			metric.WithInterval(10*time.Second))))
+	}
+	if exportStdout {
+		stdoutExporter, err := stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+		if err != nil {
+			log.Printf("Failed to create stdout metric exporter: %v", err)
+		} else {
+			metricOpts = append(metricOpts, metric.WithReader(metric.NewPeriodicReader(stdoutExporter,
+				metric.WithInterval(debugStdoutMetricInterval))))
+		}
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(metricExporter,
-			metric.WithInterval(10*time.Second))),
-		metric.WithResource(res),
-	)
+	meterProvider := metric.NewMeterProvider(metricOpts...)
 	otel.SetMeterProvider(meterProvider)
 
 	// Setup propagator
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
+	otel.SetTextMapPropagator(newPropagator(cfg.OTELPropagators))
 
+	if exportStdout {
+		log.Printf("OpenTelemetry debug output enabled, printing spans and metrics to stdout (OTLP export: %t)", exportOTLP)
+	}
 	log.Println("OpenTelemetry initialized successfully")
 
 	// Return shutdown function
@@ -73,3 +129,61 @@ func setupOTEL(ctx context.Context) func() {
 		}
 	}
 }
+
+// newPropagator combines the propagators named in OTEL_PROPAGATORS, in
+// order. Injection writes every format and extraction reads them all, the
+// last one found winning, so listing several keeps traces connected across
+// services that disagree.
+// "none" disables propagation; unknown names are skipped with a warning.
+func newPropagator(names []string) propagation.TextMapPropagator {
+	var propagators []propagation.TextMapPropagator
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "none":
+			return propagation.NewCompositeTextMapPropagator()
+		default:
+			log.Printf("Warning: ignoring unknown OTEL_PROPAGATORS entry %q", name)
+		}
+	}
+	if len(propagators) == 0 {
+		log.Println("Warning: no valid OTEL_PROPAGATORS, using tracecontext,baggage")
+		propagators = []propagation.TextMapPropagator{propagation.TraceContext{}, propagation.Baggage{}}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
+
+// otlpHeaders returns the export headers for a signal: the standard
+// OTEL_EXPORTER_OTLP_HEADERS, overridden by the signal-specific variable, plus
+// an Authorization header when a bearer token is configured. Passing headers
+// explicitly replaces the exporter's own environment handling, so both
+// variables are parsed here. Header values are never logged.
+func otlpHeaders(signal, bearerToken string) map[string]string {
+	headers := map[string]string{}
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_" + signal + "_HEADERS"} {
+		for entry := range strings.SplitSeq(os.Getenv(key), ",") {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(entry, "=")
+			name, nameErr := url.PathUnescape(strings.TrimSpace(k))
+			value, valueErr := url.PathUnescape(strings.TrimSpace(v))
+			if !ok || name == "" || nameErr != nil || valueErr != nil {
+				log.Printf("Ignoring invalid %s entry for header %q", key, strings.TrimSpace(k))
+				continue
+			}
+			headers[name] = value
+		}
+	}
+	if bearerToken != "" {
+		headers["Authorization"] = "Bearer " + bearerToken
+	}
+	return headers
+}
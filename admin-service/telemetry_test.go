@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// outgoingHeaders returns the headers a traced request made through the
+// instrumented transport carries, with the configured propagators installed
+func outgoingHeaders(t *testing.T, propagators string) http.Header {
+	t.Helper()
+	t.Setenv("OTEL_PROPAGATORS", propagators)
+	cfg := loadConfig()
+
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(newPropagator(cfg.OTELPropagators))
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	var received http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "outgoing")
+	defer span.End()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return received
+}
+
+func TestB3PropagatorInjectsSingleHeader(t *testing.T) {
+	headers := outgoingHeaders(t, "b3")
+
+	if headers.Get("b3") == "" {
+		t.Errorf("no b3 header in %v", headers)
+	}
+	for _, name := range []string{"Traceparent", "X-B3-Traceid"} {
+		if headers.Get(name) != "" {
+			t.Errorf("unexpected %s header with OTEL_PROPAGATORS=b3", name)
+		}
+	}
+}
+
+func TestB3MultiPropagatorInjectsHeaders(t *testing.T) {
+	headers := outgoingHeaders(t, "b3multi,tracecontext")
+
+	for _, name := range []string{"X-B3-Traceid", "X-B3-Spanid", "X-B3-Sampled", "Traceparent"} {
+		if headers.Get(name) == "" {
+			t.Errorf("no %s header in %v", name, headers)
+		}
+	}
+	if headers.Get("b3") != "" {
+		t.Error("unexpected single b3 header with OTEL_PROPAGATORS=b3multi")
+	}
+}
+
+func TestUnknownPropagatorsFallBackToDefaults(t *testing.T) {
+	headers := outgoingHeaders(t, "jaeger")
+
+	if headers.Get("Traceparent") == "" {
+		t.Errorf("no traceparent header in %v", headers)
+	}
+}
+
+// recordSpans replaces the package tracer with one recording every span for
+// the rest of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := tracer
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	t.Cleanup(func() { tracer = previous })
+	return recorder
+}
+
+func TestTracingSkipsExcludedPaths(t *testing.T) {
+	if got, want := loadConfig().TracingExcludePaths, []string{"/health", "/metrics", "/ready"}; !slices.Equal(got, want) {
+		t.Errorf("default TracingExcludePaths = %v, want %v", got, want)
+	}
+
+	recorder := recordSpans(t)
+	handler := tracingMiddleware([]string{"/health", "/ready"}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, path := range []string{"/health", "/ready", "/api/bookings"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s = %d, want it served", path, rec.Code)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "GET /api/bookings" {
+		var names []string
+		for _, span := range spans {
+			names = append(names, span.Name())
+		}
+		t.Errorf("spans = %v, want only GET /api/bookings", names)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tierStatsBuckets is how many slices the tier stats window is divided
+// into. Counts age out one bucket at a time, so memory is fixed by the
+// bucket count and number of tiers rather than by the approval rate.
+const tierStatsBuckets = 60
+
+type tierBucket struct {
+	start  time.Time
+	counts map[string]int
+}
+
+// TierStats counts approvals per approval tier over a sliding window. The
+// window is split into fixed buckets reused as it slides; a bucket older
+// than the window is cleared before it is written again and skipped when
+// counting, so the totals only cover roughly the last window.
+type TierStats struct {
+	mu      sync.Mutex
+	window  time.Duration
+	width   time.Duration
+	buckets [tierStatsBuckets]tierBucket
+}
+
+func NewTierStats(window time.Duration) *TierStats {
+	return &TierStats{window: window, width: max(window/tierStatsBuckets, time.Second)}
+}
+
+func (t *TierStats) enabled() bool {
+	return t.window > 0
+}
+
+// bucketFor returns the bucket covering now, clearing it if it still holds
+// counts from an earlier pass around the window.
+func (t *TierStats) bucketFor(now time.Time) *tierBucket {
+	start := now.Truncate(t.width)
+	b := &t.buckets[(start.UnixNano()/int64(t.width))%tierStatsBuckets]
+	if !b.start.Equal(start) {
+		b.start = start
+		clear(b.counts)
+	}
+	return b
+}
+
+// Record counts an approval in tier.
+func (t *TierStats) Record(tier string) {
+	if !t.enabled() {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.bucketFor(timeNow())
+	if b.counts == nil {
+		b.counts = map[string]int{}
+	}
+	b.counts[tier]++
+}
+
+// Counts returns the approvals per tier over the window, the total, and
+// when the counted window starts: the start of the oldest bucket still
+// inside it.
+func (t *TierStats) Counts() (map[string]int, int, time.Time) {
+	now := timeNow()
+	since := now.Add(-t.window)
+	counts := map[string]int{}
+	if !t.enabled() {
+		return counts, 0, now
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	total := 0
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		if !b.start.After(since) || b.start.After(now) {
+			continue
+		}
+		for tier, n := range b.counts {
+			counts[tier] += n
+			total += n
+		}
+	}
+	return counts, total, since.Truncate(t.width).Add(t.width)
+}
+
+// GetApiStatsTiers handles GET /api/stats/tiers
+func (s *AdminService) GetApiStatsTiers(w http.ResponseWriter, r *http.Request) {
+	_, span := startHandlerSpan(r, "get_tier_stats")
+	defer span.End()
+
+	if !s.tierStats.enabled() {
+		respondError(w, r, http.StatusNotFound, "Tier stats are disabled")
+		return
+	}
+
+	counts, total, since := s.tierStats.Counts()
+	// Known tiers are always listed, so a quiet tier reads as zero rather
+	// than missing
+	tiers := map[string]int{}
+	for _, tier := range s.cfg.ApprovalKnownTiers {
+		tiers[tier] = 0
+	}
+	maps.Copy(tiers, counts)
+
+	respondJSON(w, http.StatusOK, TierStatsResponse{
+		Window: s.cfg.TierStatsWindow.String(),
+		Since:  since.UTC(),
+		Tiers:  tiers,
+		Total:  total,
+	})
+}
@@ -0,0 +1,29 @@
+package main
+
+// Booking value tiers computed locally from the total price
+const (
+	valueTierLow    = "low"
+	valueTierMedium = "medium"
+	valueTierHigh   = "high"
+)
+
+// computeValueTier buckets a booking's total price: below mediumFrom is low,
+// from mediumFrom up to highFrom is medium, and highFrom or more is high.
+// Each threshold is inclusive of the tier it starts.
+func computeValueTier(price, mediumFrom, highFrom float64) string {
+	switch {
+	case price >= highFrom:
+		return valueTierHigh
+	case price >= mediumFrom:
+		return valueTierMedium
+	default:
+		return valueTierLow
+	}
+}
+
+// valueTier labels a price using the configured thresholds. It is
+// deterministic and independent of Flipt, so it is also passed to flag
+// evaluations as the pre-bucketed "value_tier" context field.
+func (s *AdminService) valueTier(price float64) string {
+	return computeValueTier(price, s.cfg.ValueTierMediumFrom, s.cfg.ValueTierHighFrom)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestComputeValueTierBoundaries(t *testing.T) {
+	for _, tc := range []struct {
+		price float64
+		want  string
+	}{
+		{0, valueTierLow},
+		{199.99, valueTierLow},
+		{200, valueTierMedium},
+		{499.99, valueTierMedium},
+		{500, valueTierHigh},
+		{10000, valueTierHigh},
+	} {
+		if got := computeValueTier(tc.price, 200, 500); got != tc.want {
+			t.Errorf("computeValueTier(%.2f) = %s, want %s", tc.price, got, tc.want)
+		}
+	}
+}
+
+func TestValueTierInResponsesAndEvaluationContext(t *testing.T) {
+	t.Setenv("VALUE_TIER_MEDIUM_FROM", "50")
+	t.Setenv("VALUE_TIER_HIGH_FROM", "100")
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setVariant("approval-tier", "standard")
+	svc := newTestService(t, evaluator, hotel, nil)
+
+	// pendingBooking costs 100, exactly the high threshold
+	rec := serve(svc, http.MethodGet, "/api/bookings/b1", "")
+	var booking ListedBooking
+	if err := json.NewDecoder(rec.Body).Decode(&booking); err != nil {
+		t.Fatal(err)
+	}
+	if booking.ValueTier != valueTierHigh {
+		t.Errorf("booking value_tier = %q, want %q", booking.ValueTier, valueTierHigh)
+	}
+
+	if _, err := svc.evaluateApprovalRules(context.Background(), &booking.Booking); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := evaluator.evaluated("approval-tier"); len(reqs) != 1 || reqs[0].Context["value_tier"] != valueTierHigh {
+		t.Errorf("approval-tier evaluations = %+v, want value_tier %q in the context", reqs, valueTierHigh)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errWriteNotPersisted is returned, wrapped, when a booking read back after
+// an accepted update doesn't have the status that was written
+var errWriteNotPersisted = errors.New("booking update was not persisted")
+
+// updateBooking sends a booking update to the hotel service and drops the
+// booking from the booking cache. With
+// VerifyWrites it then reads the booking back and fails if the hotel service
+// accepted the update without persisting the new status. A failed read-back
+// is logged but doesn't fail the update, which the hotel service did accept.
+func (s *AdminService) updateBooking(ctx context.Context, bookingID string, update hotelclient.BookingUpdateRequest) error {
+	// Even a failed update may have been applied, so the cached booking is
+	// dropped either way
+	defer s.bookings.Invalidate(bookingID)
+
+	if err := s.hotelClient.UpdateBooking(ctx, bookingID, update); err != nil {
+		return err
+	}
+	if !s.cfg.VerifyWrites {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+	stored, err := s.hotelClient.GetBooking(ctx, bookingID)
+	if err != nil {
+		log.Printf("Warning: could not read back booking %s to verify its update: %v", bookingID, err)
+		span.SetAttributes(attribute.String("write_verification", "error"))
+		s.recordWriteVerification(ctx, "error")
+		return nil
+	}
+
+	if stored.Status != update.Status {
+		log.Printf("Booking %s was updated to %s but reads back as %s", bookingID, update.Status, stored.Status)
+		span.SetAttributes(attribute.String("write_verification", "mismatch"))
+		s.recordWriteVerification(ctx, "mismatch")
+		return fmt.Errorf("%w: status is %s, expected %s", errWriteNotPersisted, stored.Status, update.Status)
+	}
+
+	span.SetAttributes(attribute.String("write_verification", "match"))
+	s.recordWriteVerification(ctx, "match")
+	return nil
+}
+
+func (s *AdminService) recordWriteVerification(ctx context.Context, result string) {
+	metricsFromContext(ctx).Add(ctx, s.writeVerificationCounter, 1, attribute.String("result", result))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestVerifyWritesReadsBackUpdates(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		verify    bool
+		persisted bool
+		err       error
+		result    string
+	}{
+		{name: "persisted update", verify: true, persisted: true, result: "match"},
+		{name: "update accepted but not persisted", verify: true, err: errWriteNotPersisted, result: "mismatch"},
+		{name: "verification disabled"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := recordMetrics(t)
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+			if !tc.persisted {
+				// Accept updates without applying them
+				hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+					if r.Method != http.MethodPatch {
+						return false
+					}
+					w.Write([]byte(`{}`))
+					return true
+				}
+			}
+			svc := newTestService(t, newFakeEvaluator(), hotel, func(cfg *Config) { cfg.VerifyWrites = tc.verify })
+
+			err := svc.updateBooking(context.Background(), "b1", hotelclient.BookingUpdateRequest{Status: "confirmed"})
+			if !errors.Is(err, tc.err) {
+				t.Errorf("updateBooking = %v, want %v", err, tc.err)
+			}
+
+			reads := hotel.requested(http.MethodGet)
+			if !tc.verify {
+				if len(reads) > 0 {
+					t.Errorf("read back %v with verification disabled", reads)
+				}
+				return
+			}
+			if len(reads) != 1 {
+				t.Errorf("read back %v, want the booking once", reads)
+			}
+			counts := collectMetric(t, reader, "admin_write_verifications_total").Data.(metricdata.Sum[int64]).DataPoints
+			if len(counts) != 1 || counts[0].Value != 1 {
+				t.Fatalf("admin_write_verifications_total = %+v, want one verification", counts)
+			}
+			if result, _ := counts[0].Attributes.Value("result"); result.AsString() != tc.result {
+				t.Errorf("verification result = %q, want %q", result.AsString(), tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/flipt-io/labs/admin-service/api"
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const maxWebhookBodyBytes = 64 << 10
+
+// PostApiWebhooksBookingCreated receives booking-created events pushed by the
+// hotel service and processes the booking immediately when auto-approval is
+// enabled. The polling worker remains as a backstop for missed events.
+func (s *AdminService) PostApiWebhooksBookingCreated(w http.ResponseWriter, r *http.Request, params api.PostApiWebhooksBookingCreatedParams) {
+	ctx, span := startHandlerSpan(r, "webhook_booking_created")
+	defer span.End()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if s.cfg.WebhookSecret != "" {
+		signature := ""
+		if params.XWebhookSignature != nil {
+			signature = *params.XWebhookSignature
+		}
+		if !validWebhookSignature(s.cfg.WebhookSecret, body, signature) {
+			span.SetAttributes(attribute.Bool("signature_valid", false))
+			respondError(w, r, http.StatusUnauthorized, "Invalid signature")
+			return
+		}
+	}
+
+	var event api.BookingCreatedEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.BookingId == "" {
+		respondError(w, r, http.StatusBadRequest, "Invalid event payload")
+		return
+	}
+
+	span.SetAttributes(attribute.String("booking_id", event.BookingId))
+
+	// Always fetch the booking rather than trusting the event payload
+	booking, err := s.hotelClient.GetBooking(ctx, event.BookingId)
+	if err != nil {
+		if errors.Is(err, hotelclient.ErrNotFound) {
+			span.SetAttributes(attribute.Bool("found", false))
+			respondError(w, r, http.StatusNotFound, "Booking not found")
+			return
+		}
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to fetch booking")
+		return
+	}
+
+	if booking.Status != "pending" {
+		respondJSON(w, http.StatusOK, WebhookResponse{
+			BookingID: booking.BookingID,
+			Processed: false,
+			Message:   "Booking is already " + booking.Status,
+		})
+		return
+	}
+
+	if s.killSwitchActive(ctx) {
+		respondJSON(w, http.StatusOK, WebhookResponse{
+			BookingID: booking.BookingID,
+			Processed: false,
+			Message:   "Auto-approval is halted by the kill switch; booking left for manual review",
+		})
+		return
+	}
+
+	if !s.autoApprovalEnabled(ctx) {
+		respondJSON(w, http.StatusOK, WebhookResponse{
+			BookingID: booking.BookingID,
+			Processed: false,
+			Message:   "Auto-approval is disabled; booking left for manual review",
+		})
+		return
+	}
+
+	// Auto-rejection reasons are localized for the booking's guest, falling
+	// back on the caller's language
+	ctx = withLanguage(ctx, preferredLanguage(r.Header.Get("Accept-Language")))
+
+	outcome, err := s.processBooking(ctx, booking)
+	if err != nil {
+		log.Printf("Error processing booking %s from webhook: %v", booking.BookingID, err)
+		span.RecordError(err)
+		respondError(w, r, http.StatusInternalServerError, "Failed to process booking")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, WebhookResponse{
+		BookingID: booking.BookingID,
+		Processed: true,
+		Message:   "Booking " + outcome,
+	})
+}
+
+// validWebhookSignature checks a "sha256=<hex>" HMAC-SHA256 signature of body.
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
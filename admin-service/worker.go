@@ -1,25 +1,114 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"log"
+	"math/rand/v2"
+	"slices"
 	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Worker processing orders for WORKER_PROCESSING_ORDER
+const (
+	orderAsReturned     = "as-returned"
+	orderOldestFirst    = "oldest-first"
+	orderHighestPrice   = "highest-price-first"
+	orderSoonestCheckin = "soonest-checkin-first"
 )
 
+var processingOrders = []string{orderAsReturned, orderOldestFirst, orderHighestPrice, orderSoonestCheckin}
+
 type AutoApprovalWorker struct {
 	svc          *AdminService
+	tenant       string
 	pollInterval time.Duration
+
+	// resumeAt is set when the hotel service rate-limits a sweep; ticks
+	// before it are skipped to honor Retry-After.
+	resumeAt time.Time
+	// halted tracks whether the kill switch stopped the last tick, so the
+	// halt is logged when it starts and ends rather than every tick
+	halted bool
+	// overloaded tracks whether the last tick ran despite the auto-approval
+	// flag because the backlog was over BACKLOG_AUTO_APPROVE_THRESHOLD
+	overloaded bool
+
+	backlogCounter      metric.Int64Counter
+	deferredCounter     metric.Int64Counter
+	haltCounter         metric.Int64Counter
+	partialFetchCounter metric.Int64Counter
+	processedCounter    metric.Int64Counter
+	remainingGauge      metric.Int64Gauge
+
+	// Lifetime totals for the shutdown summary, kept alongside the metrics
+	startedAt time.Time
+	approved  int
+	rejected  int
+	skipped   int
+	errors    int
 }
 
-func NewAutoApprovalWorker(svc *AdminService) *AutoApprovalWorker {
+// NewAutoApprovalWorker creates a worker for a tenant. svc must evaluate
+// flags in the tenant's Flipt namespace.
+func NewAutoApprovalWorker(svc *AdminService, tenant string, pollInterval time.Duration) *AutoApprovalWorker {
+	deferredCounter, _ := meter.Int64Counter(
+		"admin_worker_deferred_bookings_total",
+		metric.WithDescription("Total number of pending bookings deferred to a later tick after rate limiting"),
+	)
+
+	processedCounter, _ := meter.Int64Counter(
+		"admin_worker_processed_bookings_total",
+		metric.WithDescription("Total number of pending bookings processed by the auto-approval worker"),
+	)
+
+	haltCounter, _ := meter.Int64Counter(
+		"admin_worker_killswitch_halts_total",
+		metric.WithDescription("Total number of auto-approval worker ticks skipped because the kill switch was active"),
+	)
+
+	partialFetchCounter, _ := meter.Int64Counter(
+		"admin_worker_partial_fetches_total",
+		metric.WithDescription("Total number of worker sweeps that processed only the pending bookings fetched before a page failed"),
+	)
+
+	backlogCounter, _ := meter.Int64Counter(
+		"admin_worker_backlog_overrides_total",
+		metric.WithDescription("Total number of auto-approval worker ticks run despite the auto-approval flag because the pending backlog was over the threshold"),
+	)
+
+	remainingGauge, _ := meter.Int64Gauge(
+		"admin_worker_remaining_bookings",
+		metric.WithDescription("Number of pending bookings left unprocessed at the end of the last sweep"),
+	)
+
 	return &AutoApprovalWorker{
-		svc:          svc,
-		pollInterval: 10 * time.Second,
+		svc:                 svc,
+		tenant:              tenant,
+		pollInterval:        pollInterval,
+		backlogCounter:      backlogCounter,
+		deferredCounter:     deferredCounter,
+		haltCounter:         haltCounter,
+		partialFetchCounter: partialFetchCounter,
+		processedCounter:    processedCounter,
+		remainingGauge:      remainingGauge,
 	}
 }
 
 func (w *AutoApprovalWorker) Start(ctx context.Context) {
-	log.Println("Starting auto-approval worker...")
+	log.Printf("Starting auto-approval worker for tenant %s (every %s)...", w.tenant, w.pollInterval)
+	w.startedAt = timeNow()
+	if !slices.Contains(processingOrders, w.svc.cfg.WorkerProcessingOrder) {
+		log.Printf("Warning: unknown worker processing order %q, processing bookings as returned", w.svc.cfg.WorkerProcessingOrder)
+	}
+
+	w.waitUntilReady(ctx)
 
 	ticker := time.NewTicker(w.pollInterval)
 	defer ticker.Stop()
@@ -27,38 +116,342 @@ func (w *AutoApprovalWorker) Start(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Auto-approval worker stopped")
+			if w.svc.cfg.WorkerShutdownSummary {
+				w.logSummary()
+			}
+			log.Printf("Auto-approval worker for tenant %s stopped", w.tenant)
 			return
 		case <-ticker.C:
-			if w.svc.autoApprovalEnabled(ctx) {
-				log.Println("Auto-approval worker check - enabled")
-				w.processBookings(ctx)
+			w.tick(ctx)
+		}
+	}
+}
+
+// tick runs one sweep unless the worker is paused by rate limiting, halted
+// by the kill switch or auto-approval is disabled. The kill switch is checked
+// before the auto-approval flag, so it wins when both are on. A pending
+// backlog over BACKLOG_AUTO_APPROVE_THRESHOLD runs the sweep even with the
+// flag off, but never past the kill switch. Ticks that run are always
+// traced; skipped ticks are traced at WorkerTickSpanSampleRatio, enough to
+// show the worker's cadence and why it isn't acting without a span every
+// poll interval.
+func (w *AutoApprovalWorker) tick(ctx context.Context) {
+	ctx = withMetricsRecorder(ctx, NewMetricsRecorder(
+		attribute.String("tenant", w.tenant),
+		attribute.String("operation", "worker_sweep"),
+	))
+
+	skipReason := ""
+	switch {
+	case timeNow().Before(w.resumeAt):
+		skipReason = "paused"
+	case w.checkKillSwitch(ctx):
+		skipReason = "killswitch"
+	case w.svc.autoApprovalEnabled(ctx):
+		w.overloaded = false
+	case !w.checkBacklog(ctx):
+		skipReason = "flag_disabled"
+	}
+
+	if skipReason != "" {
+		if rand.Float64() < w.svc.cfg.WorkerTickSpanSampleRatio {
+			_, span := tracer.Start(ctx, "worker_tick", trace.WithAttributes(
+				attribute.String("tenant", w.tenant),
+				attribute.Bool("ran", false),
+				attribute.String("skip_reason", skipReason),
+			))
+			span.End()
+		}
+		return
+	}
+
+	ctx, span := tracer.Start(ctx, "worker_tick", trace.WithAttributes(
+		attribute.String("tenant", w.tenant),
+		attribute.Bool("ran", true),
+		attribute.Bool("backlog_override", w.overloaded),
+	))
+	defer span.End()
+
+	if w.overloaded {
+		log.Printf("Auto-approval worker check for tenant %s - flag disabled, running for pending backlog", w.tenant)
+	} else {
+		log.Printf("Auto-approval worker check for tenant %s - enabled", w.tenant)
+	}
+	w.processBookings(ctx)
+}
+
+// checkKillSwitch reports whether the kill switch is active, counting each
+// halted tick and logging when a halt starts or ends.
+func (w *AutoApprovalWorker) checkKillSwitch(ctx context.Context) bool {
+	active := w.svc.killSwitchActive(ctx)
+	if active != w.halted {
+		if active {
+			log.Printf("Warning: auto-approval worker for tenant %s halted by kill switch flag %s", w.tenant, w.svc.cfg.KillSwitchFlagKey)
+		} else {
+			log.Printf("Auto-approval worker for tenant %s resumed, kill switch flag %s cleared", w.tenant, w.svc.cfg.KillSwitchFlagKey)
+		}
+		w.halted = active
+	}
+	if active {
+		metricsFromContext(ctx).Add(ctx, w.haltCounter, 1)
+	}
+	return active
+}
+
+// checkBacklog reports whether the pending backlog is over
+// BACKLOG_AUTO_APPROVE_THRESHOLD, so the sweep should run although the
+// auto-approval flag is off. It is only called with the flag off. Each
+// overridden tick is counted, and the override is logged when it starts and
+// ends. A backlog that can't be counted doesn't override the flag.
+func (w *AutoApprovalWorker) checkBacklog(ctx context.Context) bool {
+	threshold := w.svc.cfg.BacklogAutoApproveThreshold
+	overloaded := false
+	if threshold > 0 {
+		_, total, err := w.svc.getBookingsPage(ctx, "pending", 0, 1)
+		if err != nil {
+			log.Printf("Warning: counting pending bookings for the backlog threshold: %v", err)
+		} else {
+			overloaded = total > threshold
+		}
+		if overloaded != w.overloaded {
+			if overloaded {
+				log.Printf("Warning: %d pending bookings for tenant %s exceed BACKLOG_AUTO_APPROVE_THRESHOLD=%d, auto-approving although the auto-approval flag is disabled", total, w.tenant, threshold)
+			} else {
+				log.Printf("Pending backlog for tenant %s back under BACKLOG_AUTO_APPROVE_THRESHOLD=%d, auto-approval follows the flag again", w.tenant, threshold)
 			}
 		}
 	}
+	w.overloaded = overloaded
+	if overloaded {
+		metricsFromContext(ctx).Add(ctx, w.backlogCounter, 1)
+	}
+	return overloaded
+}
+
+// fetchPending fetches the bookings to process this tick: every pending
+// booking, or at most WorkerMaxFetch of them, leaving the rest for later
+// ticks. With WorkerFetchPageSize set they are fetched a page at a time;
+// when a page fails after others succeeded, the fetched pages are processed.
+// Every tick fetches from the first page: decided bookings leave the pending
+// list, so the bookings a failed page held move up to where the next tick
+// reads, while resuming at the failed offset would skip over them.
+func (w *AutoApprovalWorker) fetchPending(ctx context.Context) ([]hotelclient.Booking, error) {
+	pageSize, maxFetch := w.svc.cfg.WorkerFetchPageSize, w.svc.cfg.WorkerMaxFetch
+	if pageSize <= 0 {
+		if maxFetch <= 0 {
+			return w.svc.getBookings(ctx, "pending")
+		}
+		pageSize = maxFetch
+	}
+
+	span := trace.SpanFromContext(ctx)
+
+	var bookings []hotelclient.Booking
+	offset, pages, total := 0, 0, 0
+	for {
+		limit := pageSize
+		if maxFetch > 0 {
+			limit = min(limit, maxFetch-len(bookings))
+		}
+
+		page, pageTotal, err := w.svc.getBookingsPage(ctx, "pending", offset, limit)
+		if err != nil {
+			// Processing what was fetched would only be rate limited too
+			var rateLimitErr *hotelclient.RateLimitError
+			if pages == 0 || errors.As(err, &rateLimitErr) {
+				return nil, err
+			}
+			log.Printf("Warning: fetching pending bookings failed at offset %d after %d page(s), processing the %d fetched: %v", offset, pages, len(bookings), err)
+			span.RecordError(err)
+			span.SetAttributes(attribute.Int("fetch_failed_offset", offset))
+			metricsFromContext(ctx).Add(ctx, w.partialFetchCounter, 1)
+			return bookings, nil
+		}
+
+		// A hotel service that ignores the limit still doesn't get to grow
+		// the sweep
+		if len(page) > limit {
+			page = page[:limit]
+		}
+		pages++
+		total = pageTotal
+		bookings = append(bookings, page...)
+		offset += len(page)
+		if len(page) == 0 || offset >= total || (maxFetch > 0 && len(bookings) >= maxFetch) {
+			break
+		}
+	}
+
+	remaining := max(total-offset, 0)
+	span.SetAttributes(
+		attribute.Int("pending_total", total),
+		attribute.Int("fetched_pages", pages),
+		attribute.Bool("fetch_capped", remaining > 0),
+	)
+	if remaining > 0 {
+		log.Printf("Fetched %d of %d pending bookings (WORKER_MAX_FETCH=%d), leaving %d for later ticks", len(bookings), total, maxFetch, remaining)
+	}
+	return bookings, nil
+}
+
+// waitUntilReady blocks until Flipt and the hotel service are reachable so
+// early ticks don't spam errors or make decisions on a cold client. The wait
+// is bounded; after the timeout the worker starts anyway.
+func (w *AutoApprovalWorker) waitUntilReady(ctx context.Context) {
+	timeout := w.svc.cfg.WorkerReadyTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		err := w.svc.checkReady(ctx)
+		if err == nil {
+			log.Println("Auto-approval worker dependencies ready")
+			return
+		}
+		log.Printf("Auto-approval worker waiting for dependencies: %v", err)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Auto-approval worker dependencies not ready after %s, starting anyway", timeout)
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 func (w *AutoApprovalWorker) processBookings(ctx context.Context) {
 	ctx, span := tracer.Start(ctx, "worker_process_bookings")
 	defer span.End()
 
+	span.SetAttributes(attribute.String("tenant", w.tenant))
+
 	// Fetch pending bookings using hotel client
-	bookings, err := w.svc.getBookings(ctx, "pending")
+	bookings, err := w.fetchPending(ctx)
 	if err != nil {
 		log.Printf("Error fetching pending bookings: %v", err)
 		span.RecordError(err)
+		w.backoffIfRateLimited(err)
 		return
 	}
 
 	if len(bookings) == 0 {
+		metricsFromContext(ctx).RecordGauge(ctx, w.remainingGauge, 0)
 		return
 	}
 
 	log.Printf("Processing %d pending bookings", len(bookings))
+	sortBookings(bookings, w.svc.cfg.WorkerProcessingOrder)
+	span.SetAttributes(attribute.String("processing_order", w.svc.cfg.WorkerProcessingOrder))
+
+	start := timeNow()
+	processed := 0
+	defer func() {
+		remaining := len(bookings) - processed
+		span.SetAttributes(
+			attribute.Int("processed_bookings", processed),
+			attribute.Int("remaining_bookings", remaining),
+		)
+		m := metricsFromContext(ctx)
+		m.Add(ctx, w.processedCounter, int64(processed))
+		m.RecordGauge(ctx, w.remainingGauge, int64(remaining))
+	}()
+
+	for i, booking := range bookings {
+		// Stop cleanly once the sweep has used its time budget; the
+		// remaining bookings are still pending and picked up next tick.
+		if maxDuration := w.svc.cfg.WorkerMaxSweepDuration; maxDuration > 0 && timeNow().Sub(start) >= maxDuration {
+			log.Printf("Sweep exceeded %s, processed %d bookings, %d remaining", maxDuration, processed, len(bookings)-processed)
+			return
+		}
 
-	for _, booking := range bookings {
-		if err := w.svc.processBooking(ctx, &booking); err != nil {
+		outcome, err := w.svc.processBooking(ctx, &booking)
+		if err != nil {
 			log.Printf("Error processing booking %s: %v", booking.BookingID, err)
+			w.errors++
+
+			// Stop hammering the hotel service for the rest of this sweep
+			if w.backoffIfRateLimited(err) {
+				deferred := len(bookings) - i
+				log.Printf("Rate limited by hotel service, deferring %d bookings to a later tick", deferred)
+				span.SetAttributes(attribute.Int("deferred_bookings", deferred))
+				metricsFromContext(ctx).Add(ctx, w.deferredCounter, int64(deferred))
+				return
+			}
+		}
+
+		switch outcome {
+		case outcomeApproved:
+			w.approved++
+		case outcomeRejected:
+			w.rejected++
+		case outcomeSkipped:
+			w.skipped++
 		}
+		processed++
+	}
+}
+
+// sortBookings orders a sweep's bookings by the configured strategy, so the
+// bookings that matter most are decided first when a sweep runs out of time.
+// Ties, and bookings whose creation time can't be parsed, keep the order the
+// hotel service returned them in.
+func sortBookings(bookings []hotelclient.Booking, order string) {
+	switch order {
+	case orderOldestFirst:
+		slices.SortStableFunc(bookings, func(a, b hotelclient.Booking) int {
+			ac, aErr := a.Created()
+			bc, bErr := b.Created()
+			if aErr != nil || bErr != nil {
+				// Unparseable timestamps sort last
+				return cmp.Compare(boolRank(aErr != nil), boolRank(bErr != nil))
+			}
+			return ac.Compare(bc)
+		})
+	case orderHighestPrice:
+		slices.SortStableFunc(bookings, func(a, b hotelclient.Booking) int {
+			return cmp.Compare(b.TotalPrice, a.TotalPrice)
+		})
+	case orderSoonestCheckin:
+		// Checkin dates are YYYY-MM-DD, so they sort lexically
+		slices.SortStableFunc(bookings, func(a, b hotelclient.Booking) int {
+			return cmp.Compare(a.Checkin, b.Checkin)
+		})
+	}
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// logSummary logs what the worker did over its lifetime, so short-lived runs
+// and demos show their impact without a metrics backend.
+func (w *AutoApprovalWorker) logSummary() {
+	log.Printf("Auto-approval worker for tenant %s summary: approved=%d rejected=%d skipped=%d errors=%d uptime=%s",
+		w.tenant, w.approved, w.rejected, w.skipped, w.errors, timeNow().Sub(w.startedAt).Round(time.Second))
+}
+
+// backoffIfRateLimited pauses the worker when err is a rate-limit error,
+// honoring Retry-After and falling back to the configured backoff.
+func (w *AutoApprovalWorker) backoffIfRateLimited(err error) bool {
+	var rateLimitErr *hotelclient.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return false
+	}
+
+	backoff := rateLimitErr.RetryAfter
+	if backoff <= 0 {
+		backoff = w.svc.cfg.WorkerRateLimitBackoff
 	}
+	w.resumeAt = timeNow().Add(backoff)
+	return true
 }
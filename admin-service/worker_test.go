@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipt-io/labs/admin-service/hotelclient"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTenantWorkersDecideOnlyTheirHotels(t *testing.T) {
+	hotel := newFakeHotelService(t,
+		pendingBooking("b1", "hotel_1"),
+		pendingBooking("b2", "hotel_2"),
+		pendingBooking("b3", "hotel_3"),
+	)
+	configure := func(cfg *Config) {
+		cfg.FliptNamespace = "default"
+		cfg.WorkerTenantHotels = map[string]string{"hotel_2": "partners"}
+	}
+	svc := newTestService(t, newFakeEvaluator(), hotel, configure)
+
+	partnerCfg := svc.cfg
+	partnerCfg.FliptNamespace = "partners"
+	partners := svc.newTenantService(newFakeEvaluator(), hotel.client().Scoped(svc.cfg.tenantHotelScope("partners")), partnerCfg)
+	own := svc.newTenantService(svc.evaluator, hotel.client().Scoped(svc.cfg.tenantHotelScope("default")), svc.cfg)
+
+	partnerBookings, err := NewAutoApprovalWorker(partners, "partners", time.Second).fetchPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownBookings, err := NewAutoApprovalWorker(own, "default", time.Second).fetchPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bookingIDs(partnerBookings); len(got) != 1 || got[0] != "b2" {
+		t.Errorf("partners fetched %v, want [b2]", got)
+	}
+	if got := bookingIDs(ownBookings); len(got) != 2 || got[0] != "b1" || got[1] != "b3" {
+		t.Errorf("default fetched %v, want [b1 b3]", got)
+	}
+}
+
+func TestTenantServiceSharesStores(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	svc := newTestService(t, newFakeEvaluator(), hotel, nil)
+	tenant := svc.newTenantService(newFakeEvaluator(), hotel.client(), svc.cfg)
+
+	if tenant.auditLog != svc.auditLog || tenant.events != svc.events || tenant.jobs != svc.jobs ||
+		tenant.decisions != svc.decisions || tenant.bookings != svc.bookings || tenant.tierStats != svc.tierStats {
+		t.Fatal("tenant service doesn't share the audit log, events, jobs, caches and stats")
+	}
+
+	booking := hotel.booking("b1")
+	if err := tenant.rejectBooking(context.Background(), &booking, reasonNoAvailability, "no rooms", true); err != nil {
+		t.Fatal(err)
+	}
+	if entries := svc.auditLog.ForBooking("b1"); len(entries) == 0 {
+		t.Error("tenant decision missing from the service's audit log")
+	}
+}
+
+func bookingIDs(bookings []hotelclient.Booking) []string {
+	ids := make([]string, len(bookings))
+	for i, booking := range bookings {
+		ids[i] = booking.BookingID
+	}
+	return ids
+}
+
+func TestPartialFetchReachesFailedPageNextTick(t *testing.T) {
+	hotel := newFakeHotelService(t,
+		pendingBooking("b1", "hotel_1"),
+		pendingBooking("b2", "hotel_1"),
+		pendingBooking("b3", "hotel_1"),
+		pendingBooking("b4", "hotel_1"),
+		pendingBooking("b5", "hotel_1"),
+	)
+	// The second page fails on the first sweep only
+	failed := false
+	hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/api/bookings" && r.URL.Query().Get("offset") == "2" && !failed {
+			failed = true
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+			return true
+		}
+		return false
+	}
+
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("require-manual-review", false)
+	evaluator.setVariant("approval-tier", "standard")
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+		cfg.WorkerFetchPageSize = 2
+	})
+	worker := NewAutoApprovalWorker(svc, "default", time.Second)
+
+	worker.processBookings(context.Background())
+	if !failed {
+		t.Fatal("second page was never requested")
+	}
+	for _, id := range []string{"b1", "b2"} {
+		if status := hotel.booking(id).Status; status != "confirmed" {
+			t.Errorf("after partial sweep %s is %s, want confirmed", id, status)
+		}
+	}
+
+	worker.processBookings(context.Background())
+	for _, id := range []string{"b3", "b4", "b5"} {
+		if status := hotel.booking(id).Status; status != "confirmed" {
+			t.Errorf("after next sweep %s is %s, want confirmed", id, status)
+		}
+	}
+}
+
+// captureLog collects the standard logger's output for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(previous) })
+	return &buf
+}
+
+func TestKillSwitchPrecedence(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		killSwitch   *bool
+		killErr      error
+		autoApproval bool
+		backlog      bool
+		swept        bool
+		halted       bool
+	}{
+		{name: "kill switch overrides auto-approval", killSwitch: ptr(true), autoApproval: true, halted: true},
+		{name: "kill switch overrides the backlog threshold", killSwitch: ptr(true), backlog: true, halted: true},
+		{name: "inactive kill switch follows auto-approval", killSwitch: ptr(false), autoApproval: true, swept: true},
+		{name: "inactive kill switch with auto-approval off", killSwitch: ptr(false)},
+		{name: "missing kill switch is inactive", autoApproval: true, swept: true},
+		{name: "failed kill switch evaluation is inactive", killErr: errors.New("connection refused"), autoApproval: true, swept: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := captureLog(t)
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"), pendingBooking("b2", "hotel_1"))
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", tc.autoApproval)
+			evaluator.setVariant("approval-tier", "standard")
+			evaluator.setBoolean("require-manual-review", false)
+			if tc.killSwitch != nil {
+				evaluator.setBoolean("auto-approval-killswitch", *tc.killSwitch)
+			}
+			if tc.killErr != nil {
+				evaluator.setError("auto-approval-killswitch", tc.killErr)
+			}
+			svc := newTestService(t, evaluator, hotel, func(cfg *Config) {
+				if tc.backlog {
+					cfg.BacklogAutoApproveThreshold = 1
+				}
+			})
+			worker := NewAutoApprovalWorker(svc, "default", time.Second)
+
+			worker.tick(context.Background())
+
+			if swept := hotel.booking("b1").Status == "confirmed"; swept != tc.swept {
+				t.Errorf("booking confirmed = %t, want %t", swept, tc.swept)
+			}
+			if worker.halted != tc.halted {
+				t.Errorf("halted = %t, want %t", worker.halted, tc.halted)
+			}
+			if len(evaluator.evaluated("auto-approval-killswitch")) != 1 {
+				t.Errorf("kill switch evaluated %d times, want once", len(evaluator.evaluated("auto-approval-killswitch")))
+			}
+			if tc.halted && len(evaluator.evaluated("auto-approval")) > 0 {
+				t.Error("auto-approval evaluated although the kill switch was active")
+			}
+			loggedError := strings.Contains(logs.String(), "Error evaluating auto-approval-killswitch")
+			if wantError := tc.killErr != nil; loggedError != wantError {
+				t.Errorf("logged kill switch error = %t, want %t: %s", loggedError, wantError, logs)
+			}
+		})
+	}
+}
+
+func TestKillSwitchDisabledIsNotEvaluated(t *testing.T) {
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", true)
+	evaluator.setBoolean("auto-approval-killswitch", true)
+	evaluator.setVariant("approval-tier", "standard")
+	evaluator.setBoolean("require-manual-review", false)
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.KillSwitchFlagKey = "" })
+
+	NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+	if n := len(evaluator.evaluated("auto-approval-killswitch")); n > 0 {
+		t.Errorf("kill switch evaluated %d times with no flag key", n)
+	}
+	if status := hotel.booking("b1").Status; status != "confirmed" {
+		t.Errorf("booking status = %s, want confirmed", status)
+	}
+}
+
+func TestRequireManualReviewLeavesBookingsPending(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		review  *bool
+		decided bool
+	}{
+		{name: "review required", review: ptr(true)},
+		{name: "review not required", review: ptr(false), decided: true},
+		{name: "missing flag defaults to no review", decided: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := captureLog(t)
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"))
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", true)
+			evaluator.setBoolean("auto-approval-killswitch", false)
+			evaluator.setVariant("approval-tier", "standard")
+			if tc.review != nil {
+				evaluator.setBoolean("require-manual-review", *tc.review)
+			}
+			svc := newTestService(t, evaluator, hotel, nil)
+
+			NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+			reviews := evaluator.evaluated("require-manual-review")
+			if len(reviews) != 1 || reviews[0].Context["hotel_id"] != "hotel_1" {
+				t.Fatalf("require-manual-review evaluations = %+v, want one with the booking's context", reviews)
+			}
+			status := hotel.booking("b1").Status
+			if decided := status != "pending"; decided != tc.decided {
+				t.Errorf("booking status = %s, want decided = %t", status, tc.decided)
+			}
+			if !tc.decided {
+				if updates := hotel.requested(http.MethodPatch); len(updates) > 0 {
+					t.Errorf("booking under review was updated: %v", updates)
+				}
+				if !strings.Contains(logs.String(), "require-manual-review flag is enabled") {
+					t.Errorf("no reason logged for leaving the booking pending: %s", logs)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkerProcessingOrder(t *testing.T) {
+	booking := func(id, created string, price float64, checkin string) hotelclient.Booking {
+		b := pendingBooking(id, "hotel_1")
+		b.CreatedAt, b.TotalPrice, b.Checkin = created, price, checkin
+		return b
+	}
+	unsorted := []hotelclient.Booking{
+		booking("b1", "2030-01-03T00:00:00", 200, "2030-02-05"),
+		booking("b2", "2030-01-01T00:00:00", 100, "2030-02-10"),
+		booking("b3", "2030-01-02T00:00:00", 300, "2030-02-01"),
+	}
+
+	for order, want := range map[string][]string{
+		orderAsReturned:     {"b1", "b2", "b3"},
+		orderOldestFirst:    {"b2", "b3", "b1"},
+		orderHighestPrice:   {"b3", "b1", "b2"},
+		orderSoonestCheckin: {"b3", "b1", "b2"},
+		"unknown":           {"b1", "b2", "b3"},
+	} {
+		hotel := newFakeHotelService(t, unsorted...)
+		evaluator := newFakeEvaluator()
+		evaluator.setBoolean("auto-approval", true)
+		evaluator.setBoolean("auto-approval-killswitch", false)
+		evaluator.setBoolean("require-manual-review", false)
+		evaluator.setVariant("approval-tier", "standard")
+		svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.WorkerProcessingOrder = order })
+
+		NewAutoApprovalWorker(svc, "default", time.Second).tick(context.Background())
+
+		var decided []string
+		for _, uri := range hotel.requested(http.MethodPatch) {
+			decided = append(decided, path.Base(uri))
+		}
+		if !slices.Equal(decided, want) {
+			t.Errorf("%s: decided %v, want %v", order, decided, want)
+		}
+	}
+}
+
+func TestWorkerMaxFetchLimitsPendingBookings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		pageSize int
+		ignored  bool
+		limits   []string
+		fetched  []string
+	}{
+		{name: "single capped fetch", limits: []string{"2"}, fetched: []string{"b1", "b2"}},
+		{name: "pages up to the cap", pageSize: 1, limits: []string{"1", "1"}, fetched: []string{"b1", "b2"}},
+		{name: "hotel service ignoring the limit", ignored: true, limits: []string{"2"}, fetched: []string{"b1", "b2"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := captureLog(t)
+			var bookings []hotelclient.Booking
+			for _, id := range []string{"b1", "b2", "b3", "b4", "b5"} {
+				bookings = append(bookings, pendingBooking(id, "hotel_1"))
+			}
+			hotel := newFakeHotelService(t, bookings...)
+			if tc.ignored {
+				hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+					json.NewEncoder(w).Encode(hotelclient.BookingsResponse{Bookings: bookings, Total: len(bookings)})
+					return true
+				}
+			}
+			svc := newTestService(t, newFakeEvaluator(), hotel, func(cfg *Config) {
+				cfg.WorkerMaxFetch = 2
+				cfg.WorkerFetchPageSize = tc.pageSize
+			})
+
+			fetched, err := NewAutoApprovalWorker(svc, "default", time.Second).fetchPending(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bookingIDs(fetched); !slices.Equal(got, tc.fetched) {
+				t.Errorf("fetched %v, want %v", got, tc.fetched)
+			}
+
+			var limits []string
+			for _, uri := range hotel.requested(http.MethodGet) {
+				u, err := url.Parse(uri)
+				if err != nil {
+					t.Fatal(err)
+				}
+				limits = append(limits, u.Query().Get("limit"))
+			}
+			if !slices.Equal(limits, tc.limits) {
+				t.Errorf("requested limits %v, want %v", limits, tc.limits)
+			}
+			if !strings.Contains(logs.String(), "Fetched 2 of 5 pending bookings") {
+				t.Errorf("capped fetch wasn't logged: %s", logs)
+			}
+		})
+	}
+}
+
+func TestBacklogAutoApproveThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		threshold    int
+		autoApproval bool
+		countFails   bool
+		swept        bool
+		overridden   bool
+	}{
+		{name: "disabled by default"},
+		{name: "backlog at the threshold", threshold: 2},
+		{name: "backlog over the threshold", threshold: 1, swept: true, overridden: true},
+		{name: "flag on ignores the threshold", threshold: 1, autoApproval: true, swept: true},
+		{name: "uncountable backlog follows the flag", threshold: 1, countFails: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := recordMetrics(t)
+			logs := captureLog(t)
+			hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"), pendingBooking("b2", "hotel_1"))
+			if tc.countFails {
+				hotel.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+					if r.Method == http.MethodGet && r.URL.Query().Get("limit") == "1" {
+						http.Error(w, `{"detail": "Unavailable"}`, http.StatusServiceUnavailable)
+						return true
+					}
+					return false
+				}
+			}
+			evaluator := newFakeEvaluator()
+			evaluator.setBoolean("auto-approval", tc.autoApproval)
+			evaluator.setBoolean("auto-approval-killswitch", false)
+			evaluator.setVariant("approval-tier", "standard")
+			evaluator.setBoolean("require-manual-review", false)
+			svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.BacklogAutoApproveThreshold = tc.threshold })
+			worker := NewAutoApprovalWorker(svc, "default", time.Second)
+
+			worker.tick(context.Background())
+
+			if swept := hotel.booking("b1").Status == "confirmed"; swept != tc.swept {
+				t.Errorf("booking confirmed = %t, want %t", swept, tc.swept)
+			}
+			if worker.overloaded != tc.overridden {
+				t.Errorf("overloaded = %t, want %t", worker.overloaded, tc.overridden)
+			}
+			warned := strings.Contains(logs.String(), "exceed BACKLOG_AUTO_APPROVE_THRESHOLD")
+			if warned != tc.overridden {
+				t.Errorf("logged backlog warning = %t, want %t: %s", warned, tc.overridden, logs)
+			}
+			var overrides int64
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatal(err)
+			}
+			for _, scope := range rm.ScopeMetrics {
+				for _, m := range scope.Metrics {
+					if m.Name == "admin_worker_backlog_overrides_total" {
+						for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+							overrides += dp.Value
+						}
+					}
+				}
+			}
+			if want := map[bool]int64{true: 1}[tc.overridden]; overrides != want {
+				t.Errorf("admin_worker_backlog_overrides_total = %d, want %d", overrides, want)
+			}
+			if tc.countFails && !strings.Contains(logs.String(), "Warning: counting pending bookings") {
+				t.Errorf("failed backlog count wasn't logged: %s", logs)
+			}
+		})
+	}
+}
+
+func TestBacklogOverrideEndsUnderThreshold(t *testing.T) {
+	logs := captureLog(t)
+	hotel := newFakeHotelService(t, pendingBooking("b1", "hotel_1"), pendingBooking("b2", "hotel_1"))
+	evaluator := newFakeEvaluator()
+	evaluator.setBoolean("auto-approval", false)
+	evaluator.setBoolean("auto-approval-killswitch", false)
+	evaluator.setVariant("approval-tier", "standard")
+	evaluator.setBoolean("require-manual-review", false)
+	svc := newTestService(t, evaluator, hotel, func(cfg *Config) { cfg.BacklogAutoApproveThreshold = 1 })
+	worker := NewAutoApprovalWorker(svc, "default", time.Second)
+
+	worker.tick(context.Background())
+	if !worker.overloaded {
+		t.Fatal("backlog of 2 over a threshold of 1 didn't override the flag")
+	}
+
+	// The sweep cleared the backlog, so the next tick follows the flag again
+	hotel.setStatus("b1", "pending")
+	worker.tick(context.Background())
+	if worker.overloaded {
+		t.Error("backlog of 1 at the threshold still overrides the flag")
+	}
+	if status := hotel.booking("b1").Status; status != "pending" {
+		t.Errorf("booking status = %s after the override ended, want pending", status)
+	}
+	if !strings.Contains(logs.String(), "back under BACKLOG_AUTO_APPROVE_THRESHOLD") {
+		t.Errorf("end of the override wasn't logged: %s", logs)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
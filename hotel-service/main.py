@@ -329,6 +329,7 @@ async def book_hotel(
             "total_price": total_price,
             "guest_name": booking.guest_name,
             "guest_email": booking.guest_email,
+            "locale": booking.locale,
             "checkin": booking.checkin,
             "checkout": booking.checkout,
             "guests": booking.guests,
@@ -349,10 +350,14 @@ async def book_hotel(
 
 @app.get("/api/bookings")
 async def get_bookings(
-    status: Optional[str] = Query(None, description="Filter by status (pending, confirmed, rejected)"),
+    status: Optional[str] = Query(None, description="Filter by status (pending, confirmed, rejected, cancelled)"),
+    offset: int = Query(0, ge=0, description="Number of matching bookings to skip"),
+    limit: Optional[int] = Query(None, ge=1, description="Maximum number of bookings to return (default: all)"),
+    hotel_ids: Optional[str] = Query(None, description="Comma-separated hotel IDs to include (default: all)"),
+    exclude_hotel_ids: Optional[str] = Query(None, description="Comma-separated hotel IDs to exclude"),
 ):
     """
-    Get all bookings, optionally filtered by status.
+    Get all bookings, optionally filtered by status and hotel and paginated.
     Used by admin-service to retrieve unapproved bookings.
     """
     with tracer.start_as_current_span("get_bookings") as span:
@@ -366,6 +371,21 @@ async def get_bookings(
             ]
         else:
             filtered_bookings = list(bookings_storage.values())
+
+        # Filter by hotel, so admin-service workers partitioned by hotel
+        # only see their own bookings
+        if hotel_ids:
+            included = set(hotel_ids.split(","))
+            filtered_bookings = [b for b in filtered_bookings if b["hotel_id"] in included]
+        if exclude_hotel_ids:
+            excluded = set(exclude_hotel_ids.split(","))
+            filtered_bookings = [b for b in filtered_bookings if b["hotel_id"] not in excluded]
+        
+        total = len(filtered_bookings)
+        if limit is not None:
+            filtered_bookings = filtered_bookings[offset:offset + limit]
+        elif offset:
+            filtered_bookings = filtered_bookings[offset:]
         
         # Create a copy and convert datetime to ISO string for JSON serialization
         serializable_bookings = []
@@ -382,7 +402,7 @@ async def get_bookings(
         
         return {
             "bookings": serializable_bookings,
-            "total": len(serializable_bookings),
+            "total": total,
             "status": status or "all",
         }
 
@@ -441,7 +461,7 @@ async def update_booking(
         updated = False
         if update_request.status is not None:
             # Validate status
-            valid_statuses = ["pending", "confirmed", "rejected"]
+            valid_statuses = ["pending", "confirmed", "rejected", "cancelled"]
             if update_request.status not in valid_statuses:
                 raise HTTPException(
                     status_code=400, 
@@ -456,6 +476,11 @@ async def update_booking(
             span.set_attribute("updated.confirmation_number", update_request.confirmation_number)
             updated = True
         
+        if update_request.confirmation_expires_at is not None:
+            booking["confirmation_expires_at"] = update_request.confirmation_expires_at.isoformat()
+            span.set_attribute("updated.confirmation_expires_at", booking["confirmation_expires_at"])
+            updated = True
+        
         if updated:
             booking["updated_at"] = datetime.utcnow()
             logger.info(
@@ -53,6 +53,7 @@ class BookingRequest(BaseModel):
     guests: int
     guest_name: str
     guest_email: str
+    locale: Optional[str] = Field(None, description="Guest's preferred language tag, e.g. de-CH")
 
 
 class BookingResponse(BaseModel):
@@ -67,5 +68,6 @@ class BookingResponse(BaseModel):
 
 class BookingUpdateRequest(BaseModel):
     """Booking update request for PATCH endpoint."""
-    status: Optional[str] = Field(None, description="Booking status (pending, confirmed, rejected)")
+    status: Optional[str] = Field(None, description="Booking status (pending, confirmed, rejected, cancelled)")
     confirmation_number: Optional[str] = Field(None, description="Confirmation number")
+    confirmation_expires_at: Optional[datetime] = Field(None, description="When the confirmation hold expires")